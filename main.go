@@ -1,21 +1,188 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"sort"
 	sqlairreflect "sqlairtest/reflect"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
+// ErrNoRows is returned by Scan when the statement has no rows to scan.
+// It is sql.ErrNoRows, so either of them can be used with errors.Is.
+var ErrNoRows = sql.ErrNoRows
+
+// ErrNoResults is returned by Scan for statements that have no results,
+// such as an INSERT without a RETURNING clause.
+var ErrNoResults = errors.New("no results to scan")
+
+// ErrSuperfluousType is returned by Prepare for types
+// that the statement does not use.
+var ErrSuperfluousType = errors.New("superfluous type")
+
+// ErrMissingType is returned by Prepare, wrapped with the name of the
+// type, when a type that the statement uses is not given.
+var ErrMissingType = errors.New("type info not present")
+
+// ErrDuplicateType is returned by Prepare, wrapped with
+// the name of the type, when a type is given twice.
+var ErrDuplicateType = errors.New("type not unique")
+
+// ErrNoInputRows is returned by Complete, wrapped with the input,
+// when the slice of a bulk input as in $Person[:].* is empty.
+var ErrNoInputRows = errors.New("no rows to insert")
+
+// ErrEmptySlice is returned by Complete, wrapped with the input, for an empty
+// slice in a NOT IN list. An empty IN list is NULL, which matches nothing,
+// but there is no such value for NOT IN to match everything.
+var ErrEmptySlice = errors.New("empty slice")
+
+// ErrBindLimit is returned by Exec, wrapped with the limit, when a
+// statement binds more values than the database takes.
+var ErrBindLimit = errors.New("too many values to bind")
+
+// ErrNoOutputs is returned by Get and All, wrapped with the type
+// of their results, for statements that have no outputs.
+var ErrNoOutputs = errors.New("no outputs")
+
+// ErrOutputType is returned by Get and All, wrapped with the output, when
+// an output of the statement is not of the type of their results.
+var ErrOutputType = errors.New("wrong output type")
+
+// ParseError is returned by Parse for statements that can not be parsed.
+type ParseError struct {
+	// Offset is the position in the statement, in bytes,
+	// at which the parser found the error.
+	Offset int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return e.Msg
+}
+
+// UnknownFieldError is returned when the statement uses
+// a field that its type does not have, as in $Person.typo
+type UnknownFieldError struct {
+	Type  string
+	Field string
+	// Valid holds the fields that the type does have.
+	Valid []string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("type %s has no field %s, valid fields are: %s",
+		e.Type, e.Field, strings.Join(e.Valid, ", "))
+}
+
+// unknownField returns an *UnknownFieldError for the field of the struct sf.
+func unknownField(typeName, field string, sf sqlairreflect.Struct) error {
+	return &UnknownFieldError{Type: typeName, Field: field, Valid: fieldNames(sf)}
+}
+
+// ArgumentCountError is returned when the number of arguments does not match
+// the statement, such as the parameters of Complete or the outputs of Scan.
+type ArgumentCountError struct {
+	// What is what was counted: "parameters" or "outputs".
+	What     string
+	Expected int
+	Have     int
+}
+
+func (e *ArgumentCountError) Error() string {
+	return fmt.Sprintf("%s mismatch. expected %d, have %d", e.What, e.Expected, e.Have)
+}
+
+// TypeMismatchError is returned by Scan when the value of
+// a column can not go into the output it is for.
+type TypeMismatchError struct {
+	Column     string
+	ColumnType reflect.Type
+	// Output is the field, as in Person.Name, or the type that
+	// is not a struct, as in Count, that the column is for.
+	Output     string
+	OutputType reflect.Type
+}
+
+func (e *TypeMismatchError) Error() string {
+	return fmt.Sprintf("the column %s is type %v but %s has type %v", e.Column, e.ColumnType, e.Output, e.OutputType)
+}
+
+// KindError is returned when a type or a value is not of the kind that the
+// statement needs, as with a slice for $Person[:].* or a pointer for Scan.
+type KindError struct {
+	// Name is the type or the expression, as in Person or Person.ids
+	Name string
+	// Kind is the kind that it should have been.
+	Kind reflect.Kind
+	Msg  string
+}
+
+func (e *KindError) Error() string {
+	return e.Msg
+}
+
+// InputError is returned by Prepare and Complete for an input that can not
+// be used where it is, as $Person.* outside a VALUES tuple or a SET clause.
+type InputError struct {
+	// Input is the input expression, as in $Person.*
+	Input string
+	Msg   string
+}
+
+func (e *InputError) Error() string {
+	return e.Msg
+}
+
+// SchemaError is returned by PrepareWithSchema for a table, or a column
+// of a table, that the statement uses and the database does not have.
+type SchemaError struct {
+	Table string
+	// Column is empty if the table is not in the database.
+	Column string
+	Msg    string
+}
+
+func (e *SchemaError) Error() string {
+	return e.Msg
+}
+
+// ColumnError is returned by Scan when the columns of
+// the results do not go into the fields of the outputs.
+type ColumnError struct {
+	// Columns are the columns of the results, or the
+	// fields of the outputs as in Person.name, that do not.
+	Columns []string
+	Msg     string
+}
+
+func (e *ColumnError) Error() string {
+	return e.Msg
+}
+
 type Parser struct { // AF: It'd be nice to explain what each field represents here
 	parts   []Part
 	str     string //TODO: change to input
 	parsed  int
 	skipped int
+	// aliases maps the aliases of types in output targets to
+	// the types, as in &Person.* AS boss
+	aliases map[string]string
+	// tables maps the tables in the FROM, JOIN, INTO and UPDATE clauses,
+	// and their aliases, to the tables. INTO and UPDATE map to the tables
+	// of those clauses. The common table expressions of a WITH clause map
+	// to "", they are not tables of the database.
+	tables map[string]string
 }
 
 func NewParser() *Parser {
@@ -36,9 +203,22 @@ func (p *Parser) advance() bool {
 	}
 	p.skipSpaces()
 	mark := p.skipped
-	for p.skipped < len(p.str) &&
-		(isNameByte(p.str[p.skipped]) || skipableBytes[p.str[p.skipped]]) {
-		p.skipped++
+	for p.skipped < len(p.str) {
+		if skipableBytes[p.str[p.skipped]] {
+			p.skipped++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(p.str[p.skipped:])
+		if !isNameRune(r) {
+			break
+		}
+		p.skipped += size
+	}
+	// Anything else that nobody could parse (e.g. '<', ';' or a new line)
+	// is skipped one rune at a time, otherwise the parser would not move.
+	if p.skipped == mark && p.skipped < len(p.str) {
+		_, size := utf8.DecodeRuneInString(p.str[p.skipped:])
+		p.skipped += size
 	}
 	return p.skipped != mark
 }
@@ -54,7 +234,8 @@ func (p *Parser) parseStringLiteral() error {
 				// Reached end of string
 				// and didn't find the closing quote
 				p.add(cp, &stringPart{p.str[p.parsed:]})
-				return fmt.Errorf("missing right quote in string literal")
+				p.skipped = len(p.str)
+				return p.errorf("missing right quote in string literal")
 			}
 			p.add(cp, &stringPart{p.str[cp.skipped:p.skipped]})
 			return nil
@@ -69,6 +250,7 @@ func (p *Parser) parseStringLiteral() error {
 // It should parse things like p.* in "Select p.* as..."
 // and Person.name in "Select p.name as &Person.name from..."
 // It is not an error if the qualifier OR the colName are empty // AF: you mean XOR or OR?
+// The right side can be a path such as Address.city in $Person.Address.city
 func (p *Parser) parseQualifiedExpression() (qualifiedName, error) {
 	cp := p.save()
 	var qn qualifiedName
@@ -80,7 +262,19 @@ func (p *Parser) parseQualifiedExpression() (qualifiedName, error) {
 			} else {
 				// There is nothing to the right of the '.'.
 				// This is an error
-				return qualifiedName{}, fmt.Errorf("expecting identifier after '%s.'", qn.Left)
+				return qualifiedName{}, p.errorf("expecting identifier after '%s.'", qn.Left)
+			}
+			for qn.Right != "*" {
+				dot := p.save()
+				if !p.skipByte('.') {
+					break
+				}
+				name, ok := p.parseIdentifier()
+				if !ok || name == "*" {
+					dot.restore()
+					break
+				}
+				qn.Right = qn.Right + "." + name
 			}
 		}
 		return qn, nil
@@ -108,11 +302,29 @@ func (p *Parser) parseInputExpression() error {
 	defer cp.autorestore()
 	p.skipSpaces()
 	if p.skipByte('$') {
+		// $Person.{name, age}
+		if typeName, fields, ok, err := p.parseFieldList(); err != nil {
+			return err
+		} else if ok {
+			p.add(cp, &inputPart{TypeExpr: typeField{typeName, ""}, FieldList: fields})
+			return nil
+		}
 		if qe, err := p.parseQualifiedExpression(); err == nil {
 			if qe.Left == "" {
-				return fmt.Errorf("no qualifier in input expression")
+				return p.errorf("no qualifier in input expression")
+			}
+			if !isGoIdentifier(qe.Left) {
+				// Not a Go type, it could be a placeholder like $1
+				cp.restore()
+				return nil
 			}
-			p.add(cp, &inputPart{typeField{qe.Left, qe.Right}})
+			// $Ids[:] or $Person.ids[:] expand a slice
+			slice := p.skipString("[:]")
+			// $Person[:].* expands a slice of structs into VALUES rows
+			if slice && qe.Right == "" && p.skipString(".*") {
+				qe.Right = "*"
+			}
+			p.add(cp, &inputPart{TypeExpr: typeField{qe.Left, qe.Right}, Slice: slice})
 		} else {
 			return err
 		}
@@ -120,6 +332,160 @@ func (p *Parser) parseInputExpression() error {
 	return nil
 }
 
+// parseFieldList parses a type followed by a list of some of its fields
+// in braces, such as Person.{name, age}. It returns false, leaving the
+// parser where it was, if there is no list of fields.
+func (p *Parser) parseFieldList() (string, []string, bool, error) {
+	cp := p.save()
+	typeName, ok := p.parseIdentifier()
+	if !ok || !isGoIdentifier(typeName) || !p.skipByte('.') || !p.skipByte('{') {
+		cp.restore()
+		return "", nil, false, nil
+	}
+	var fields []string
+	for {
+		p.skipSpaces()
+		field, ok := p.parseIdentifier()
+		if !ok || field == "*" {
+			return "", nil, false, p.errorf("expecting field name in '%s.{'", typeName)
+		}
+		fields = append(fields, field)
+		p.skipSpaces()
+		if p.skipByte('}') {
+			return typeName, fields, true, nil
+		}
+		if !p.skipByte(',') {
+			return "", nil, false, p.errorf("missing '}' after fields of '%s'", typeName)
+		}
+	}
+}
+
+// parseTableReference records the tables that follow FROM, JOIN, INTO and
+// UPDATE, and their aliases. It moves past the keyword and the tables only,
+// so that whatever follows them is left for the other parsers. It reports
+// whether it did move. A word after
+// a table is taken for its alias, even if it is a keyword as in FROM person
+// WHERE. That is harmless, as the tables are looked up by the qualifiers
+// of the columns.
+func (p *Parser) parseTableReference() bool {
+	cp := p.save()
+	p.skipSpaces()
+	var keyword string
+	for _, kw := range []string{"FROM", "JOIN", "INTO", "UPDATE"} {
+		if p.skipKeyword(kw) {
+			keyword = kw
+			break
+		}
+	}
+	// ON CONFLICT DO UPDATE SET and SELECT ... FOR UPDATE name no table.
+	if prev := p.previousWord(cp.skipped); keyword == "" ||
+		keyword == "UPDATE" && (strings.EqualFold(prev, "DO") || strings.EqualFold(prev, "FOR")) {
+		cp.restore()
+		return false
+	}
+	end := p.save()
+	for {
+		p.skipSpaces()
+		table, ok := p.parseTableName()
+		if !ok {
+			break
+		}
+		end = p.save()
+		p.skipSpaces()
+		// FROM generate_series(1, 3) is a function,
+		// INTO person (*) has a column list.
+		if keyword != "INTO" && p.peekByte('(') {
+			break
+		}
+		p.addTable(table, table)
+		// Columns of public.person are qualified with person.
+		if i := strings.LastIndexByte(table, '.'); i >= 0 {
+			p.addTable(table[i+1:], table)
+		}
+		if keyword == "INTO" || keyword == "UPDATE" {
+			p.addTable(keyword, table)
+		}
+		p.skipKeyword("AS")
+		p.skipSpaces()
+		if alias, ok := p.parseIdentifier(); ok && alias != "*" {
+			p.addTable(alias, table)
+		}
+		p.skipSpaces()
+		// FROM person AS p, address AS a
+		if keyword != "FROM" || !p.skipByte(',') {
+			break
+		}
+	}
+	end.restore()
+	return true
+}
+
+// parseTableName parses the name of a table, which might be qualified
+// by its schema: person or public.person
+func (p *Parser) parseTableName() (string, bool) {
+	name, ok := p.parseIdentifier()
+	if !ok || name == "*" {
+		return "", false
+	}
+	cp := p.save()
+	if p.skipByte('.') {
+		if table, ok := p.parseIdentifier(); ok && table != "*" {
+			return name + "." + table, true
+		}
+	}
+	cp.restore()
+	return name, true
+}
+
+// parseCommonTableName records the name of a common table expression of a
+// WITH clause, as x in WITH x AS (SELECT ...). It does not move the parser.
+func (p *Parser) parseCommonTableName() {
+	cp := p.save()
+	defer cp.restore()
+	p.skipSpaces()
+	name, ok := p.parseIdentifier()
+	if !ok || name == "*" {
+		return
+	}
+	p.skipSpaces()
+	if !p.skipKeyword("AS") {
+		return
+	}
+	p.skipSpaces()
+	if p.peekByte('(') {
+		p.addTable(name, "")
+	}
+}
+
+// addTable maps name to the table, unless name is a common table expression.
+func (p *Parser) addTable(name, table string) {
+	if p.tables == nil {
+		p.tables = make(map[string]string)
+	}
+	if t, ok := p.tables[table]; ok && t == "" {
+		// The alias of a common table expression
+		table = ""
+	}
+	if t, ok := p.tables[name]; ok && t == "" {
+		return
+	}
+	p.tables[name] = table
+}
+
+// previousWord returns the word that ends before the offset, if any.
+func (p *Parser) previousWord(offset int) string {
+	before := strings.TrimRightFunc(p.str[:offset], unicode.IsSpace)
+	start := len(before)
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(before[:start])
+		if !isNameRune(r) {
+			break
+		}
+		start -= size
+	}
+	return before[start:]
+}
+
 // Other names could be outputClause, queryOutputClause
 type parsedOutputPart struct {
 	// This is whatever follows the & it could be a Struct, an M a variable or even blank
@@ -145,12 +511,31 @@ func (p *Parser) parseOutputExpression() error {
 
 	p.skipSpaces()
 	if p.skipByte('&') {
+		// &Person.{id, name}
+		if typeName, fields, ok, err := p.parseFieldList(); err != nil {
+			return err
+		} else if ok {
+			targets, err := p.parseTypeAlias(fieldList(typeName, fields))
+			if err != nil {
+				return err
+			}
+			p.add(cp, &outputPart{[]tableColumn{}, targets})
+			return nil
+		}
 		if qe, err := p.parseQualifiedExpression(); err == nil {
 			if qe.Left == "" {
-				return fmt.Errorf("malformed output expression")
+				return p.errorf("malformed output expression")
+			}
+			if !isGoIdentifier(qe.Left) {
+				// Not a Go type, it could be a bitwise and like a&1
+				cp.restore()
+				return nil
+			}
+			targets, err := p.parseTypeAlias([]typeField{{qe.Left, qe.Right}})
+			if err != nil {
+				return err
 			}
-			p.add(cp, &outputPart{[]tableColumn{},
-				[]typeField{{qe.Left, qe.Right}}})
+			p.add(cp, &outputPart{[]tableColumn{}, targets})
 			return nil
 		} else {
 			return err
@@ -170,39 +555,274 @@ func (p *Parser) parseOutputExpression() error {
 			dc.Left = ""
 		}
 		p.skipSpaces()
-		if p.skipString("AS") {
+		if p.skipKeyword("AS") {
 			p.skipSpaces()
 			if p.skipByte('&') {
-				if qe, err := p.parseQualifiedExpression(); err == nil {
-					if qe.Left == "" {
-						return fmt.Errorf("malformed output expression")
-					}
-					p.add(cp, &outputPart{[]tableColumn{{dc.Left, dc.Right}},
-						[]typeField{{qe.Left, qe.Right}}})
-					return nil
-				} else {
+				targets, err := p.parseOutputTarget()
+				if err != nil {
 					return err
 				}
+				// Only p.* can go to a list of fields: p.* AS &Person.{id, name}
+				if len(targets) > 1 && dc.Right != "*" {
+					return p.errorf("column %s can not go to several fields", dc.Right)
+				}
+				p.add(cp, &outputPart{[]tableColumn{{dc.Left, dc.Right}}, targets})
+				return nil
 			}
 		}
 	} else {
 		return err
 	}
+	// Not a plain column. Try an arbitrary SQL expression such as COUNT(*),
+	// COALESCE(a.name, '') or (price * qty) as the source of the output.
+	cp.restore()
+	p.skipSpaces()
+	start := p.skipped
+	if comma, ok := p.skipParens(); ok && comma {
+		// (a, b) AS &Type.* is a column group.
+		cp.restore()
+		return nil
+	}
+	p.skipped = start
+	if p.skipExpression() {
+		expr := p.str[start:p.skipped]
+		p.skipSpaces()
+		if p.skipKeyword("AS") {
+			p.skipSpaces()
+			if p.skipByte('&') {
+				targets, err := p.parseOutputTarget()
+				if err != nil {
+					return err
+				}
+				if len(targets) > 1 {
+					return p.errorf("column %s can not go to several fields", expr)
+				}
+				p.add(cp, &outputPart{[]tableColumn{{"", expr}}, targets})
+				return nil
+			}
+		}
+	}
 	cp.restore()
 	return nil
 }
 
-func (p *Parser) parseColumnGroup() bool {
+// parseOutputTarget parses what follows the & in AS &Person.name, which can
+// also be a list of fields as in AS &Person.{id, name}.
+// The target can be followed by an alias for its type: AS &Person.name AS boss
+func (p *Parser) parseOutputTarget() ([]typeField, error) {
+	if typeName, fields, ok, err := p.parseFieldList(); err != nil {
+		return nil, err
+	} else if ok {
+		return p.parseTypeAlias(fieldList(typeName, fields))
+	}
+	qe, err := p.parseQualifiedExpression()
+	if err != nil {
+		return nil, err
+	}
+	if !isGoIdentifier(qe.Left) {
+		return nil, p.errorf("malformed output expression")
+	}
+	return p.parseTypeAlias([]typeField{{qe.Left, qe.Right}})
+}
+
+// parseTypeAlias parses the alias that can follow an output target, as in
+// &Person.* AS boss. The targets are then for the alias instead of the type.
+// An alias is a separate output of the same type, which is what a self join
+// needs, and it can be used anywhere a type can: m.name AS &boss.name
+func (p *Parser) parseTypeAlias(targets []typeField) ([]typeField, error) {
 	cp := p.save()
 	p.skipSpaces()
-	if !p.skipByte('(') {
+	if !p.skipKeyword("AS") {
+		cp.restore()
+		return targets, nil
+	}
+	p.skipSpaces()
+	alias, ok := p.parseIdentifier()
+	if !ok || !isGoIdentifier(alias) {
 		cp.restore()
+		return targets, nil
+	}
+	typeName := targets[0].Type
+	if t, found := p.aliases[typeName]; found {
+		typeName = t
+	}
+	if t, found := p.aliases[alias]; found && t != typeName {
+		return nil, p.errorf("alias %s is already used for %s", alias, t)
+	}
+	if p.aliases == nil {
+		p.aliases = make(map[string]string)
+	}
+	p.aliases[alias] = typeName
+	for i := range targets {
+		targets[i].Type = alias
+	}
+	return targets, nil
+}
+
+// fieldList returns the fields of Type.{a, b} as [Type.a Type.b]
+func fieldList(typeName string, fields []string) []typeField {
+	tfs := make([]typeField, len(fields))
+	for i, f := range fields {
+		tfs[i] = typeField{typeName, f}
+	}
+	return tfs
+}
+
+// skipExpression skips a SQL expression made of terms joined by operators.
+// It does not try to understand the expression, only to find where it ends.
+// If there is no expression the parser does not move.
+func (p *Parser) skipExpression() bool {
+	mark := p.skipped
+	if !p.skipTerm() {
+		p.skipped = mark
+		return false
+	}
+	for {
+		end := p.skipped
+		p.skipSpaces()
+		if !p.skipOperator() {
+			p.skipped = end
+			return true
+		}
+		p.skipSpaces()
+		if !p.skipTerm() {
+			p.skipped = end
+			return true
+		}
+	}
+}
+
+// skipTerm skips a single operand of an expression. That is a parenthesised
+// group, a string literal or a (qualified) name optionally followed by a
+// parenthesised argument list as in a function call.
+func (p *Parser) skipTerm() bool {
+	mark := p.skipped
+	// Unary operators.
+	for p.skipByte('-') || p.skipByte('+') || p.skipByte('~') {
+		p.skipSpaces()
+	}
+	if p.skipped >= len(p.str) {
+		p.skipped = mark
 		return false
 	}
+	switch c := p.str[p.skipped]; c {
+	case '(':
+		if _, ok := p.skipParens(); ok {
+			return true
+		}
+	case '\'', '"':
+		p.skipped++
+		if p.skipByteFind(c) {
+			return true
+		}
+	default:
+		if name, ok := p.parseIdentifier(); ok && !clauseKeywords[strings.ToUpper(name)] {
+			if !p.skipByte('.') {
+				p.skipParens()
+				return true
+			}
+			if _, ok := p.parseIdentifier(); ok {
+				p.skipParens()
+				return true
+			}
+		}
+	}
+	p.skipped = mark
+	return false
+}
+
+// clauseKeywords are the SQL keywords that end an expression. They are not
+// terms, so that SELECT -x AS &T.x is not read as the expression SELECT -x.
+var clauseKeywords = map[string]bool{
+	"SELECT": true, "DISTINCT": true, "ALL": true, "FROM": true, "WHERE": true,
+	"ON": true, "AND": true, "OR": true, "NOT": true, "BY": true, "HAVING": true,
+	"SET": true, "VALUES": true, "RETURNING": true, "WHEN": true, "THEN": true,
+	"ELSE": true,
+}
+
+// skipOperator skips a (possibly multi byte) operator like + or ||.
+// The & is not an operator since it starts output expressions.
+func (p *Parser) skipOperator() bool {
+	mark := p.skipped
+	for p.skipped < len(p.str) && strings.IndexByte("+-*/%|<>=!^", p.str[p.skipped]) >= 0 {
+		p.skipped++
+	}
+	return p.skipped != mark
+}
+
+// skipParens skips a parenthesised group, including nested groups and string
+// literals. comma reports whether there is a comma directly inside the group
+// as in (a, b). If there is no (complete) group the parser does not move.
+func (p *Parser) skipParens() (comma bool, ok bool) {
+	if !p.peekByte('(') {
+		return false, false
+	}
+	depth := 0
+	for i := p.skipped; i < len(p.str); i++ {
+		switch c := p.str[i]; c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				p.skipped = i + 1
+				return comma, true
+			}
+		case ',':
+			if depth == 1 {
+				comma = true
+			}
+		case '\'', '"':
+			j := strings.IndexByte(p.str[i+1:], c)
+			if j < 0 {
+				return false, false
+			}
+			i += j + 1
+		}
+	}
+	return false, false
+}
+
+// parseInsertColumns parses the (*) column list of an INSERT statement
+// as in INSERT INTO person (*) VALUES ($Person.*). It is only recognised
+// when VALUES comes next so that COUNT(*) and the like are left alone.
+func (p *Parser) parseInsertColumns() {
+	cp := p.save()
+	p.skipSpaces()
+	if p.skipByte('(') {
+		p.skipSpaces()
+		if p.skipByte('*') {
+			p.skipSpaces()
+			if p.skipByte(')') {
+				end := p.skipped
+				p.skipSpaces()
+				if p.skipKeyword("VALUES") {
+					p.skipped = end
+					p.add(cp, &insertColumnsPart{})
+					return
+				}
+			}
+		}
+	}
+	cp.restore()
+}
+
+func (p *Parser) parseColumnGroup() error {
+	cp := p.save()
+	p.skipSpaces()
+	if !p.skipByte('(') {
+		cp.restore()
+		return nil
+	}
 	var tclist []tableColumn
 	for p.skipped < len(p.str) && !p.peekByte(')') {
 		p.skipSpaces()
 		if tc, err := p.parseQualifiedExpression(); err == nil {
+			// As in parseOutputExpression, a lone name is a column.
+			if tc.Right == "" {
+				tc.Right = tc.Left
+				tc.Left = ""
+			}
 			tclist = append(tclist, tableColumn{tc.Left, tc.Right})
 			p.skipSpaces()
 			if !p.skipByte(',') {
@@ -214,21 +834,42 @@ func (p *Parser) parseColumnGroup() bool {
 		//FIXME: review this line
 		p.skipped++
 		p.skipSpaces()
-		if p.skipString("AS") {
+		if p.skipKeyword("AS") {
 			p.skipSpaces()
+			if p.peekByte('(') {
+				return p.parseOutputTargets(cp, tclist)
+			}
 			if !p.skipByte('&') {
+				// Something like (a, b) AS pair is not an output
+				// expression, leave it for the database.
 				cp.restore()
-				fmt.Println("expected '&'")
-				return false
+				p.skipByteFind(')')
+				return nil
 			}
-			if tp, err := p.parseQualifiedExpression(); err == nil {
-				p.add(cp, &outputPart{Columns: tclist, Fields: []typeField{{tp.Left, tp.Right}}})
-				return true
-			} else {
-				fmt.Println("expecting AS <TypeDefinition>")
-				cp.restore()
-				return false
+			if typeName, fields, ok, err := p.parseFieldList(); err != nil {
+				return err
+			} else if ok {
+				// (a, b) AS &Person.{x, y} is (a, b) AS (&Person.x, &Person.y)
+				if len(fields) != len(tclist) {
+					return p.errorf("column group has %d columns but %d output targets", len(tclist), len(fields))
+				}
+				targets, err := p.parseTypeAlias(fieldList(typeName, fields))
+				if err != nil {
+					return err
+				}
+				p.add(cp, &outputPart{Columns: tclist, Fields: targets})
+				return nil
 			}
+			tp, err := p.parseQualifiedExpression()
+			if err != nil || !isGoIdentifier(tp.Left) {
+				return p.errorf("malformed output expression")
+			}
+			targets, err := p.parseTypeAlias([]typeField{{tp.Left, tp.Right}})
+			if err != nil {
+				return err
+			}
+			p.add(cp, &outputPart{Columns: tclist, Fields: targets})
+			return nil
 		} else {
 			// If there is no AS, it is not an error.
 			// This is just a parenthesized group of things
@@ -237,40 +878,84 @@ func (p *Parser) parseColumnGroup() bool {
 			// But it is not our purpose to check SQL syntax.
 			cp.restore()
 			p.skipByteFind(')')
-			return false
+			return nil
 		}
 	}
 
+	// Not a column group. advance moves past the '(' only, so that
+	// whatever comes right after it, like $Ids[:], is parsed.
 	cp.restore()
-	p.skipped++
-	p.skipped++
-	return false
+	return nil
 }
 
-// AF: So this parses a name such as address in Person.address,
-// which can also be *
-func (p *Parser) parseIdentifier() (string, bool) {
-	if p.skipped >= len(p.str) {
-		return "", false
+// parseOutputTargets parses the parenthesised list of targets in a column
+// group such as (p.name, a.city) AS (&Person.name, &Address.city).
+// Columns and targets are paired in the order they are written, so there
+// must be as many targets as there are columns.
+func (p *Parser) parseOutputTargets(cp *checkpoint, tclist []tableColumn) error {
+	p.skipByte('(')
+	p.skipSpaces()
+	if !p.peekByte('&') {
+		// Not a list of output targets, leave it for the database.
+		cp.restore()
+		p.skipByteFind(')')
+		return nil
 	}
-	if p.peekByte('*') {
+	var tflist []typeField
+	for {
+		p.skipSpaces()
+		if !p.skipByte('&') {
+			return p.errorf("expecting '&' in output target list")
+		}
+		tp, err := p.parseQualifiedExpression()
+		if err != nil {
+			return err
+		}
+		if !isGoIdentifier(tp.Left) {
+			return p.errorf("malformed output expression")
+		}
+		targets, err := p.parseTypeAlias([]typeField{{tp.Left, tp.Right}})
+		if err != nil {
+			return err
+		}
+		tflist = append(tflist, targets...)
+		p.skipSpaces()
+		if !p.skipByte(',') {
+			break
+		}
+	}
+	if !p.skipByte(')') {
+		return p.errorf("missing ')' in output target list")
+	}
+	if len(tflist) != len(tclist) {
+		return p.errorf("column group has %d columns but %d output targets", len(tclist), len(tflist))
+	}
+	p.add(cp, &outputPart{Columns: tclist, Fields: tflist})
+	return nil
+}
+
+// AF: So this parses a name such as address in Person.address,
+// which can also be *
+func (p *Parser) parseIdentifier() (string, bool) {
+	if p.skipped >= len(p.str) {
+		return "", false
+	}
+	if p.peekByte('*') {
 		p.skipped++
 		return "*", true
 	}
 	mark := p.skipped
-	if !isNameByte(p.str[p.skipped]) {
-		return "", false
-	}
-	// could you not write for i := p.skipped; i < len(p.str); i++ {...
-	// and leave out the var i int?
-	var i int
-	for i = p.skipped; i < len(p.str); i++ {
-		if !isNameByte(p.str[i]) {
+	for p.skipped < len(p.str) {
+		r, size := utf8.DecodeRuneInString(p.str[p.skipped:])
+		if !isNameRune(r) {
 			break
 		}
+		p.skipped += size
 	}
-	p.skipped = i
-	return p.str[mark:i], true
+	if p.skipped == mark {
+		return "", false
+	}
+	return p.str[mark:p.skipped], true
 }
 
 func (p *Parser) peekByte(b byte) bool {
@@ -298,10 +983,11 @@ func (p *Parser) skipByteFind(b byte) bool {
 func (p *Parser) skipSpaces() bool {
 	mark := p.skipped
 	for p.skipped < len(p.str) {
-		if p.str[p.skipped] != ' ' {
+		r, size := utf8.DecodeRuneInString(p.str[p.skipped:])
+		if !unicode.IsSpace(r) {
 			break
 		}
-		p.skipped++
+		p.skipped += size
 	}
 	return p.skipped != mark
 }
@@ -314,10 +1000,41 @@ func (p *Parser) skipString(s string) bool {
 	return false
 }
 
-// Could do with a better name, prehaps isAlphanumericByte or something
-func isNameByte(c byte) bool {
-	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' ||
-		'0' <= c && c <= '9' || c == '_'
+// skipKeyword skips the keyword kw (case insensitive) only if it appears as a
+// whole word in the input. Identifiers such as "asset" or "ASC" are not mistaken
+// for the keyword "AS" because the runes around kw must not be name runes.
+func (p *Parser) skipKeyword(kw string) bool {
+	if r, _ := utf8.DecodeLastRuneInString(p.str[:p.skipped]); isNameRune(r) {
+		return false
+	}
+	end := p.skipped + len(kw)
+	if end > len(p.str) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(p.str[end:]); isNameRune(r) {
+		return false
+	}
+	return p.skipString(kw)
+}
+
+// isNameRune reports whether r can be part of a SQL identifier.
+// Most databases accept any Unicode letter or digit in identifiers.
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// isGoIdentifier reports whether s follows Go's rules for identifiers,
+// which type names in the DSL must do.
+func isGoIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
 }
 
 func (p *Parser) add(cp *checkpoint, part Part) {
@@ -370,24 +1087,52 @@ func (cp *checkpoint) autorestore() {
 // [stringPart outputPart stringPart inputPart]
 type ParsedExpr struct {
 	parts []Part
+	// aliases maps the type aliases in the statement to their types
+	aliases map[string]string
+	// tables maps the tables of the statement and their aliases to the
+	// tables, see Parser.tables
+	tables map[string]string
 }
 
-func generateOutputInfo(op *outputPart, targetInfo sqlairreflect.Info) OutputInfo {
-	targetStruct := targetInfo.(sqlairreflect.Struct)
-
-	tagNameList := make([]string, 0)
-	for tagName, _ := range targetStruct.Fields { // range over a map iterates over key/value pairs
-		tagNameList = append(tagNameList, tagName)
+func generateOutputInfo(op *outputPart, typeName string, argTypes typeMap) (OutputInfo, error) {
+	targetStruct, ok := argTypes[typeName].(sqlairreflect.Struct)
+	if !ok {
+		return scalarOutputInfo(op, typeName, argTypes)
 	}
 
+	// The tags follow the order of the fields in the struct.
+	tagNameList := targetStruct.Order
+
 	outputCols := make([]string, 0)
 
-	// This assumes that the user has written SELECT &Person.* FROM, we need to take account of SELECT &Person.name FROM
+	// SELECT &Person.{id, name} FROM or SELECT p.* AS &Person.{id, name} FROM
+	if op.isFieldList() {
+		tags, err := op.fieldListTags(targetStruct)
+		if err != nil {
+			return OutputInfo{}, err
+		}
+		return OutputInfo{tags, typeName}, nil
+	}
+
+	// SELECT &Person.* FROM or SELECT &Person.name FROM
 	if len(op.Columns) == 0 {
-		outputCols = append(outputCols, tagNameList...)
+		if tag, found := fieldTag(targetStruct, op.Fields[0].Field); found {
+			outputCols = append(outputCols, outputColumn(op.Fields[0].Field, tag))
+		} else {
+			outputCols = append(outputCols, tagNameList...)
+		}
 	}
 
-	for _, column := range op.Columns {
+	for i, column := range op.Columns {
+		if len(op.Fields) > 1 && op.Fields[i].Type != typeName {
+			continue
+		}
+		// A column going to a single field is the output of the
+		// field's tag, whatever the column's own name is.
+		if tag := op.columnTag(i, argTypes); tag != "" {
+			outputCols = append(outputCols, outputColumn(op.Fields[i].Field, tag))
+			continue
+		}
 		colName := column.Column
 		if colName == "*" {
 			outputCols = append(outputCols, tagNameList...)
@@ -401,7 +1146,46 @@ func generateOutputInfo(op *outputPart, targetInfo sqlairreflect.Info) OutputInf
 		}
 
 	}
-	return OutputInfo{outputCols, targetStruct.Name()}
+	return OutputInfo{outputCols, typeName}, nil
+}
+
+// outputColumn returns the name that Scan uses for the output of the column
+// tag to the field of the DSL. That is the tag itself unless the field is in
+// a nested struct, where the path to it is kept: Address.city
+func outputColumn(field, tag string) string {
+	if i := strings.LastIndexByte(field, '.'); i >= 0 {
+		return field[:i+1] + tag
+	}
+	return tag
+}
+
+// scalarOutputInfo returns the output information for a type that is not a
+// struct, such as type Count int in COUNT(*) AS &Count. Such a type holds
+// the value of a single column, which has to be given in the output.
+func scalarOutputInfo(op *outputPart, typeName string, argTypes typeMap) (OutputInfo, error) {
+	if len(op.Columns) == 0 {
+		return OutputInfo{}, &KindError{typeName, reflect.Struct,
+			fmt.Sprintf("output of %s needs a column, as in COUNT(*) AS &%s", typeName, typeName)}
+	}
+	var outputCols []string
+	for i := range op.Columns {
+		if len(op.Fields) > 1 && op.Fields[i].Type != typeName {
+			continue
+		}
+		tag := op.columnTag(i, argTypes)
+		if tag == "" {
+			return OutputInfo{}, &KindError{typeName, reflect.Struct,
+				fmt.Sprintf("%s can not hold several columns", typeName)}
+		}
+		outputCols = append(outputCols, tag)
+	}
+	return OutputInfo{outputCols, typeName}, nil
+}
+
+// scalarColumn is the name of the output column
+// of a type that is not a struct: count for &Count
+func scalarColumn(typeName string) string {
+	return strings.ToLower(typeName)
 }
 
 func (pe *ParsedExpr) Prepare(args ...any) (*PreparedExpr, error) {
@@ -409,6 +1193,15 @@ func (pe *ParsedExpr) Prepare(args ...any) (*PreparedExpr, error) {
 	if err != nil {
 		return &PreparedExpr{}, err
 	}
+	// Aliases have the type information of their types.
+	for alias, typeName := range pe.aliases {
+		if _, ok := argTypes[alias]; ok {
+			return nil, fmt.Errorf("%w: alias %s is also the name of a type", ErrDuplicateType, alias)
+		}
+		if info, ok := argTypes[typeName]; ok {
+			argTypes[alias] = info
+		}
+	}
 	if err := pe.interpret(argTypes); err != nil {
 		return nil, err
 	}
@@ -419,14 +1212,40 @@ func (pe *ParsedExpr) Prepare(args ...any) (*PreparedExpr, error) {
 		switch part.(type) {
 		case *outputPart:
 			op := part.(*outputPart)
-			outputInfo := generateOutputInfo(op, argTypes[op.Fields[0].Type])
-			outputInfos = append(outputInfos, outputInfo)
+			for _, typeName := range op.TypeNames() {
+				outputInfo, err := generateOutputInfo(op, typeName, argTypes)
+				if err != nil {
+					return nil, err
+				}
+				outputInfos = append(outputInfos, outputInfo)
+			}
 		}
 
 	}
 	return &PreparedExpr{pe, outputInfos, argTypes}, nil
 }
 
+// PrepareWithSchema prepares the expression like Prepare and then checks it
+// against the tables of the database db. The columns that the outputs read,
+// including the ones that &Type.* and t.* AS &Type.* expand to, and the
+// columns that inputs write with (*) or SET must exist in their tables, and
+// the Go fields must be able to hold the declared types of the columns.
+// Tables that the database knows nothing about, such as those defined with
+// a WITH clause, are not checked.
+func (pe *ParsedExpr) PrepareWithSchema(db *sql.DB, args ...any) (*PreparedExpr, error) {
+	prepared, err := pe.Prepare(args...)
+	if err != nil {
+		return nil, err
+	}
+	s := &schema{db: db, aliases: pe.tables, tables: make(map[string]tableSchema)}
+	for _, c := range prepared.schemaColumns() {
+		if err := s.check(c); err != nil {
+			return nil, err
+		}
+	}
+	return prepared, nil
+}
+
 // interpret walks the input expression tree to ensure:
 // - Each input/output target in expression has type information in argTypes.
 // - All type information is actually required by the input/output targets.
@@ -441,6 +1260,17 @@ func (pe *ParsedExpr) interpret(argTypes typeMap) error {
 			if seen, err = pe.validateExpressionType(e.(TypeMappingExpression), argTypes, seen); err != nil {
 				return err
 			}
+			if err = validateFields(e, argTypes); err != nil {
+				return err
+			}
+			if ip, ok := e.(*inputPart); ok && ip.isBulk() && !ip.InValues {
+				return &InputError{ip.expression(),
+					fmt.Sprintf("input %s can only be used right after VALUES, without parentheses", ip.expression())}
+			}
+			if ip, ok := e.(*inputPart); ok && ip.isMultiField() && !ip.InValues && !ip.Assign && !ip.InTuple {
+				return &InputError{ip.expression(),
+					fmt.Sprintf("input %s can only be used in a VALUES tuple or a SET clause", ip.expression())}
+			}
 		}
 	}
 
@@ -448,28 +1278,103 @@ func (pe *ParsedExpr) interpret(argTypes typeMap) error {
 	// for. If unused types were supplied, it is an error condition.
 	for name := range argTypes {
 		if _, ok := seen[name]; !ok {
-			return fmt.Errorf("superfluous type")
+			return ErrSuperfluousType
 		}
 	}
 
 	return nil
 }
 
-// validateExpressionType ensures that the type name identity from the input
-// expression is present in the input type information. If it is not, an error
-// is returned. The list of seen types is updated and returned.
+// validateExpressionType ensures that the type name identities from the input
+// expression are present in the input type information. If one is not, an
+// error is returned. The list of seen types is updated and returned.
 func (pe *ParsedExpr) validateExpressionType(
 	exp TypeMappingExpression, argTypes typeMap, seen map[string]bool,
 ) (map[string]bool, error) {
-	typeName := exp.TypeName()
-	if _, ok := argTypes[typeName]; !ok {
-		return seen, fmt.Errorf("type info not present (%s)", typeName)
+	for _, typeName := range exp.TypeNames() {
+		if t, ok := pe.aliases[typeName]; ok {
+			// The type of an alias is used as well.
+			if _, ok := argTypes[t]; !ok {
+				return seen, fmt.Errorf("%w (%s)", ErrMissingType, t)
+			}
+			seen[t] = true
+		}
+		if _, ok := argTypes[typeName]; !ok {
+			return seen, fmt.Errorf("%w (%s)", ErrMissingType, typeName)
+		}
+		seen[typeName] = true
 	}
-
-	seen[typeName] = true
 	return seen, nil
 }
 
+// validateFields ensures that every field used in an input or output
+// expression, such as name in $Person.name or &Person.{id, name}, is a
+// tagged field of its struct. Types that are not structs, such as
+// type PersonID int, have no fields and are used on their own: $PersonID
+func validateFields(p Part, argTypes typeMap) error {
+	var fields []typeField
+	switch p := p.(type) {
+	case *inputPart:
+		_, isStruct := argTypes[p.TypeExpr.Type].(sqlairreflect.Struct)
+		if isStruct && p.TypeExpr.Field == "" && len(p.FieldList) == 0 {
+			return &InputError{p.expression(), fmt.Sprintf("input %s needs a field of %s, as in $%s.* or $%s.field",
+				p.expression(), p.TypeExpr.Type, p.TypeExpr.Type, p.TypeExpr.Type)}
+		}
+		fields = append(fields, p.TypeExpr)
+		for _, f := range p.FieldList {
+			fields = append(fields, typeField{p.TypeExpr.Type, f})
+		}
+	case *outputPart:
+		fields = p.Fields
+	}
+	for _, f := range fields {
+		if f.Field == "" {
+			continue
+		}
+		sf, ok := argTypes[f.Type].(sqlairreflect.Struct)
+		if !ok {
+			return &KindError{f.Type, reflect.Struct,
+				fmt.Sprintf("type %s is not a struct, it has no field %s", f.Type, f.Field)}
+		}
+		if f.Field == "*" {
+			continue
+		}
+		if _, _, found := lookupField(sf, f.Field); !found {
+			return unknownField(f.Type, f.Field, sf)
+		}
+	}
+	return nil
+}
+
+// fieldNames returns the names of all the fields of the struct that can be
+// used in the DSL, in the order in which they are declared. Those are the
+// tags of the fields and the paths to the tagged fields of nested structs.
+func fieldNames(sf sqlairreflect.Struct) []string {
+	type named struct {
+		index int
+		names []string
+	}
+	var all []named
+	for tag, f := range sf.Fields {
+		all = append(all, named{f.Index, []string{tag}})
+	}
+	for name, n := range sf.Nested {
+		var names []string
+		for _, nestedName := range fieldNames(n.Struct) {
+			names = append(names, name+"."+nestedName)
+		}
+		all = append(all, named{n.Index, names})
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].index < all[j].index
+	})
+	var names []string
+	for _, n := range all {
+		names = append(names, n.names...)
+	}
+	return names
+}
+
 func (pe *ParsedExpr) String() string {
 	out := "ParsedExpr["
 	for i, p := range pe.parts {
@@ -523,153 +1428,1260 @@ func (pe *PreparedExpr) Complete(arguments ...any) (*CompletedExpr, error) {
 	ce.arguments = arguments
 	ce.outputSpecs = pe.OutputSpecs
 	ioparts := 0
+	for _, p := range pe.Parsed.parts {
+		switch p := p.(type) {
+		case *inputPart:
+			ioparts++
+		case *outputPart:
+			ioparts += len(p.TypeNames())
+		}
+	}
+	if ioparts != len(arguments) {
+		return nil, &ArgumentCountError{"parameters", ioparts, len(arguments)}
+	}
+	var ai int // The argument for the current input/output part
+	var si int // The output spec for the current output part
 	for _, p := range pe.Parsed.parts {
 		switch p := p.(type) {
 		case *stringPart:
 			ce.Add(p.Chunk)
 		case *inputPart:
-			ioparts++
-			str, _ := p.ToSql()
+			str, err := p.ToSql(arguments[ai], pe.ArgTypes)
+			if err != nil {
+				return nil, err
+			}
+			if p.isBulk() {
+				// Keep track of the rows so that Exec can split them
+				// across several statements if there are too many.
+				if ce.bulk != nil {
+					return nil, &InputError{p.expression(), "only one bulk input allowed per statement"}
+				}
+				row, n := p.row(pe.ArgTypes)
+				start := ce.sb.Len()
+				ce.bulk = &bulkRows{start, start + len(str), row, n}
+			}
 			ce.Add(str)
+			ai++
 		case *outputPart:
-			ioparts++
-			str, _ := p.ToSql(pe)
+			ai += len(p.TypeNames())
+			str, _ := p.ToSql(pe, si)
+			si += len(p.TypeNames())
+			ce.Add(str)
+		case *insertColumnsPart:
+			str, err := p.ToSql(pe.ArgTypes)
+			if err != nil {
+				return nil, err
+			}
 			ce.Add(str)
 		}
 	}
-	if ioparts != len(arguments) {
-		return nil, fmt.Errorf("parameters mismatch. expected %d, have %d", ioparts, len(arguments))
+	return &ce, nil
+}
+
+type CompletedExpr struct {
+	sb          strings.Builder
+	arguments   []any
+	outputSpecs []OutputInfo
+	rows        *sql.Rows
+	bulk        *bulkRows
+	// bindLimit overrides the maximum number of values bound to a
+	// statement. Zero means the limit of the database driver.
+	// See SetBindLimit.
+	bindLimit int
+	scanMode  ScanMode
+	report    ScanReport
+}
+
+// ScanMode says what Scan does when the results and the outputs
+// do not match one to one.
+type ScanMode int
+
+const (
+	// ScanLenient ignores the columns of the results that no output
+	// consumes and leaves the fields of the outputs that have no
+	// column untouched, as Person.id in SELECT &Person.name FROM ...
+	ScanLenient ScanMode = iota
+	// ScanStrict makes Scan fail on either of them.
+	ScanStrict
+)
+
+// ScanReport lists the fields of the outputs, as in Person.name, that
+// Scan filled and those that it left untouched. The fields of an output are
+// those that &Type.* fills in, with any other field that a column went to.
+// An output that several output expressions fill in is listed once.
+type ScanReport struct {
+	Filled    []string
+	Untouched []string
+}
+
+// SetScanMode sets the mode of the next calls to Scan. It is ScanLenient
+// unless set otherwise.
+func (ce *CompletedExpr) SetScanMode(mode ScanMode) {
+	ce.scanMode = mode
+}
+
+// ScanReport returns the report of the last call to Scan.
+func (ce *CompletedExpr) ScanReport() ScanReport {
+	return ce.report
+}
+
+// bulkRows describes where the VALUES rows of a bulk input such as
+// $Person[:].* are in the completed SQL statement.
+type bulkRows struct {
+	// start and end are the offsets of the rows in the statement.
+	start int
+	end   int
+	// row holds the placeholders of a single row.
+	row string
+	// rowArgs is the number of values bound to each row.
+	rowArgs int
+}
+
+// add pushes a new piece to the SQL statement that will be ready to be executed
+// in the DB
+func (ce *CompletedExpr) Add(str string) {
+	ce.sb.WriteString(str)
+	ce.sb.WriteString(" ")
+}
+
+func (ce *CompletedExpr) Sql() string {
+	str := ce.sb.String()
+	if str[len(str)-1] == ' ' {
+		return str[:len(str)-1]
+	}
+	return str
+}
+
+func (ce *CompletedExpr) Exec(db *sql.DB, parts []Part, argTypes typeMap) error {
+	return ce.ExecContext(context.Background(), db, parts, argTypes)
+}
+
+// ExecContext is Exec with a context for running the statement.
+func (ce *CompletedExpr) ExecContext(ctx context.Context, db *sql.DB, parts []Part, argTypes typeMap) error {
+	return ce.exec(ctx, db, bindLimit(db.Driver()), parts, argTypes)
+}
+
+// ExecTx is ExecContext for a statement that runs in the transaction tx.
+// A bulk INSERT that is split in several statements runs all of them in tx.
+// The driver of a transaction is not known, so unless SetBindLimit says
+// otherwise, a statement binds at most 999 values, the lowest limit of
+// the databases that we know of.
+func (ce *CompletedExpr) ExecTx(ctx context.Context, tx *sql.Tx, parts []Part, argTypes typeMap) error {
+	return ce.exec(ctx, tx, defaultBindLimit, parts, argTypes)
+}
+
+// SetBindLimit sets the maximum number of values bound to a statement, for
+// databases configured with another limit than the default of their driver.
+// Bulk inserts with more values are split in several statements.
+func (ce *CompletedExpr) SetBindLimit(limit int) {
+	ce.bindLimit = limit
+}
+
+// execer runs statements, it is a *sql.DB or a *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// exec runs the statement with db, binding at most limit values to each
+// statement unless the limit is set with SetBindLimit.
+func (ce *CompletedExpr) exec(ctx context.Context, db execer, limit int, parts []Part, argTypes typeMap) error {
+	// In order to execute the query, we need to pass the proper arguments
+	// so they can be bound. Get the input parts and pass them one at a
+	// time.
+	var bindArgs []any
+	var bulkStart, bulkArgs int // The bound values of the bulk input, if any
+	var pi int                  // AF: A little confusing. I guess its the counter for which argument we are currently on
+	for _, part := range parts {
+		switch part.(type) {
+		case *inputPart:
+			ip := part.(*inputPart)
+			values, err := ip.bindValues(ce.arguments[pi], argTypes)
+			if err != nil {
+				return err
+			}
+			if ip.isBulk() {
+				bulkStart, bulkArgs = len(bindArgs), len(values)
+			}
+			bindArgs = append(bindArgs, values...)
+			pi++
+		case *outputPart:
+			pi += len(part.(*outputPart).TypeNames())
+		}
+	}
+	if ce.bindLimit != 0 {
+		limit = ce.bindLimit
+	}
+	// Statements without outputs, such as most INSERTs, have no rows to
+	// scan. Some drivers would not even run them until the rows are read.
+	// Queries keep their rows for ScanMaps.
+	if len(ce.outputSpecs) == 0 && !returnsRows(parts) {
+		if ce.bulk != nil && len(bindArgs) > limit {
+			return ce.execBatches(ctx, db, bindArgs, bulkStart, bulkArgs, limit)
+		}
+		res, err := db.ExecContext(ctx, ce.Sql(), bindArgs...)
+		if err != nil {
+			return err
+		}
+		if isInsert(parts) {
+			return ce.setInsertId(res, parts, argTypes)
+		}
+		return nil
+	}
+	// Queries, and statements with outputs in a RETURNING clause such as
+	// INSERT ... RETURNING id AS &Person.id, have rows to scan.
+	// The latter might not run until Scan reads the rows. Their rows can
+	// not be split across several statements, as Scan reads one result.
+	if ce.bulk != nil && len(bindArgs) > limit {
+		return fmt.Errorf("%w: a bulk insert with outputs binds %d values, more than %d",
+			ErrBindLimit, len(bindArgs), limit)
+	}
+	var err error
+	ce.rows, err = db.QueryContext(ctx, ce.Sql(), bindArgs...)
+	if err != nil {
+		return err
+	}
+
+	//Just for printing the rows:
+	//  var name string
+	//  if err := ce.rows.Scan(&name); err != nil {
+	//      return err
+	//  }
+	//fmt.Printf("\nThe rows %+v\n", ce.rows)
+	return nil
+}
+
+// execBatches runs a bulk INSERT that binds more than limit values as several
+// statements, each with as many rows as fit, in a single transaction: the
+// one that db is, or a new one of db.
+// bindArgs[bulkStart:bulkStart+bulkArgs] are the values of the rows.
+func (ce *CompletedExpr) execBatches(ctx context.Context, db execer, bindArgs []any, bulkStart, bulkArgs, limit int) error {
+	if db, ok := db.(*sql.DB); ok {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if err := ce.execBatches(ctx, tx, bindArgs, bulkStart, bulkArgs, limit); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	}
+
+	b := ce.bulk
+	stmt := ce.sb.String()
+	before := bindArgs[:bulkStart]
+	rows := bindArgs[bulkStart : bulkStart+bulkArgs]
+	after := bindArgs[bulkStart+bulkArgs:]
+	perStmt := (limit - len(before) - len(after)) / b.rowArgs
+	if perStmt < 1 {
+		return fmt.Errorf("%w: can not bind a single row in less than %d values", ErrBindLimit, limit)
+	}
+
+	numRows := bulkArgs / b.rowArgs
+	for i := 0; i < numRows; i += perStmt {
+		n := perStmt
+		if numRows-i < n {
+			n = numRows - i
+		}
+		batch := stmt[:b.start] + strings.TrimSuffix(strings.Repeat(b.row+", ", n), ", ") + stmt[b.end:]
+		var args []any
+		args = append(args, before...)
+		args = append(args, rows[i*b.rowArgs:(i+n)*b.rowArgs]...)
+		args = append(args, after...)
+		if _, err := db.ExecContext(ctx, batch, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemaColumn is a column read by an output or written by an input.
+type schemaColumn struct {
+	// table is the table, or its alias, that qualifies the column, if any.
+	// into is the table of the INSERT or UPDATE of an input column.
+	table string
+	into  string
+	name  string
+	// output is the Go value for the column, as in Person.Name, and typ
+	// its type, which is nil if unknown.
+	output string
+	typ    reflect.Type
+}
+
+// kind returns the kind of the Go value for the column, Invalid if unknown.
+func (c schemaColumn) kind() reflect.Kind {
+	if c.typ == nil {
+		return reflect.Invalid
+	}
+	return c.typ.Kind()
+}
+
+// schemaColumns returns the columns of the database that the outputs and
+// inputs of the expression go to, with the types of their Go values.
+func (pe *PreparedExpr) schemaColumns() []schemaColumn {
+	var columns []schemaColumn
+	// fieldColumn adds the column for the field name of the DSL in the
+	// struct of the type typeName.
+	fieldColumn := func(table, into, column, typeName string, sf sqlairreflect.Struct, name string) {
+		c := schemaColumn{table: table, into: into, name: column}
+		if f, found := fieldOf(sf, name); found {
+			c.output, c.typ = typeName+"."+f.Name, f.Type()
+		}
+		columns = append(columns, c)
+	}
+	// structColumns adds the columns of the tags of a struct.
+	structColumns := func(table, into, typeName string, sf sqlairreflect.Struct, tags []string) {
+		for _, tag := range tags {
+			fieldColumn(table, into, tag, typeName, sf, tag)
+		}
+	}
+	for _, part := range pe.Parsed.parts {
+		switch p := part.(type) {
+		case *outputPart:
+			sf, isStruct := pe.ArgTypes[p.Fields[0].Type].(sqlairreflect.Struct)
+			if p.isFieldList() {
+				var table string
+				if len(p.Columns) == 1 {
+					table = p.Columns[0].Table
+				}
+				// Prepare has already checked the fields.
+				tags, _ := p.fieldListTags(sf)
+				structColumns(table, "", p.Fields[0].Type, sf, tags)
+				continue
+			}
+			if len(p.Columns) == 0 {
+				if tag, found := fieldTag(sf, p.Fields[0].Field); found {
+					fieldColumn("", "", tag, p.Fields[0].Type, sf, p.Fields[0].Field)
+				} else if isStruct {
+					structColumns("", "", p.Fields[0].Type, sf, sf.Order)
+				}
+				continue
+			}
+			for i, c := range p.Columns {
+				typeName := p.Fields[0].Type
+				if len(p.Fields) == len(p.Columns) {
+					typeName = p.Fields[i].Type
+				}
+				info := pe.ArgTypes[typeName]
+				sf, isStruct := info.(sqlairreflect.Struct)
+				switch {
+				case c.Column == "*" && isStruct:
+					structColumns(c.Table, "", typeName, sf, sf.Order)
+				case !isColumnName(c.Column):
+					// COUNT(*) or any other expression
+				case !isStruct:
+					columns = append(columns, schemaColumn{c.Table, "", c.Column, typeName, info.Type()})
+				case p.columnTag(i, pe.ArgTypes) != "":
+					fieldColumn(c.Table, "", c.Column, typeName, sf, p.Fields[i].Field)
+				default:
+					// (a.district, a.street) AS &Address.*
+					fieldColumn(c.Table, "", c.Column, typeName, sf, c.Column)
+				}
+			}
+		case *insertColumnsPart:
+			for _, ip := range p.Inputs {
+				if sf, ok := pe.ArgTypes[ip.TypeExpr.Type].(sqlairreflect.Struct); ok {
+					structColumns("", "INTO", ip.TypeExpr.Type, sf, ip.schemaTags(sf))
+				}
+			}
+		case *inputPart:
+			if sf, ok := pe.ArgTypes[p.TypeExpr.Type].(sqlairreflect.Struct); ok && p.Assign {
+				structColumns("", "UPDATE", p.TypeExpr.Type, sf, p.schemaTags(sf))
+			}
+		}
+	}
+	return columns
+}
+
+// schemaTags returns the tags of the columns that
+// the input writes with (*) or SET.
+func (ip *inputPart) schemaTags(sf sqlairreflect.Struct) []string {
+	if ip.isMultiField() {
+		tags, _ := ip.tags(sf)
+		return tags
+	}
+	if tag, found := fieldTag(sf, ip.TypeExpr.Field); found {
+		return []string{tag}
+	}
+	return nil
+}
+
+// fieldOf returns the field name of the DSL in the struct,
+// which might be in a nested struct as in Address.city.
+func fieldOf(sf sqlairreflect.Struct, name string) (sqlairreflect.Field, bool) {
+	path := strings.Split(name, ".")
+	for _, step := range path[:len(path)-1] {
+		nested, found := sf.Nested[step]
+		if !found {
+			return sqlairreflect.Field{}, false
+		}
+		sf = nested.Struct
+	}
+	tag, found := fieldTag(sf, path[len(path)-1])
+	if !found {
+		return sqlairreflect.Field{}, false
+	}
+	return sf.Fields[tag], true
+}
+
+// isColumnName reports whether s is the name of a column and not an
+// expression such as COUNT(*).
+func isColumnName(s string) bool {
+	for _, r := range s {
+		if !isNameRune(r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// tableSchema maps the columns of a table to the sort of their values,
+// as declaredSort and dataTypeSort give it for their types.
+type tableSchema map[string]string
+
+// schema checks columns against the tables of a database,
+// reading the columns of each table only once.
+type schema struct {
+	db *sql.DB
+	// aliases maps the tables of the statement and their aliases to the
+	// tables. INTO and UPDATE map to the tables of those clauses.
+	aliases map[string]string
+	tables  map[string]tableSchema
+}
+
+// check returns an error if the column c is not in its table or if a Go
+// value of its kind can not hold the values of the column.
+func (s *schema) check(c schemaColumn) error {
+	var candidates []string
+	switch {
+	case c.into != "":
+		candidates = []string{s.aliases[c.into]}
+	case c.table != "":
+		candidates = []string{s.aliases[c.table]}
+	default:
+		// An unqualified column is in one of the tables of the statement.
+		seen := make(map[string]bool)
+		for name, table := range s.aliases {
+			if name != "INTO" && name != "UPDATE" && !seen[table] {
+				seen[table] = true
+				candidates = append(candidates, table)
+			}
+		}
+		sort.Strings(candidates)
+	}
+	var known []string
+	for _, table := range candidates {
+		if table == "" {
+			continue
+		}
+		ts, err := s.table(table)
+		if err != nil {
+			return err
+		}
+		if len(ts) == 0 {
+			return &SchemaError{table, "", fmt.Sprintf("table %s not found in the database", table)}
+		}
+		known = append(known, table)
+		if colSort, found := ts[c.name]; found {
+			if !compatibleKind(c.kind(), colSort) {
+				return &TypeMismatchError{table + "." + c.name, sortType(colSort), c.output, c.typ}
+			}
+			return nil
+		}
+	}
+	if len(known) == 0 {
+		return nil
+	}
+	tables := strings.Join(known, ", ")
+	return &SchemaError{tables, c.name, fmt.Sprintf("column %s not found in table %s", c.name, tables)}
+}
+
+// table returns the columns of the table, which are empty
+// if the database does not have such a table.
+func (s *schema) table(name string) (tableSchema, error) {
+	if ts, ok := s.tables[name]; ok {
+		return ts, nil
+	}
+	ts, err := readTableSchema(s.db, name)
+	if err != nil {
+		return nil, err
+	}
+	s.tables[name] = ts
+	return ts, nil
+}
+
+// readTableSchema reads the columns of the table from the database, with
+// PRAGMA table_info on SQLite and from information_schema on the rest.
+// A table that is not qualified by its schema is in the current schema.
+func readTableSchema(db *sql.DB, table string) (tableSchema, error) {
+	schemaName, name := "", table
+	if i := strings.LastIndexByte(table, '.'); i >= 0 {
+		schemaName, name = table[:i], table[i+1:]
+	}
+	args := []any{name}
+	var query string
+	sortOf := dataTypeSort
+	if _, ok := db.Driver().(*sqlite3.SQLiteDriver); ok {
+		sortOf = declaredSort
+		query = "SELECT name, type FROM pragma_table_info(?)"
+		if schemaName != "" {
+			query = "SELECT name, type FROM pragma_table_info(?, ?)"
+			args = append(args, schemaName)
+		}
+	} else {
+		placeholder, currentSchema := dialect(db.Driver())
+		query = "SELECT column_name, data_type FROM information_schema.columns " +
+			"WHERE table_name = " + placeholder(1) + " AND table_schema = " + currentSchema
+		if schemaName != "" {
+			query = "SELECT column_name, data_type FROM information_schema.columns " +
+				"WHERE table_name = " + placeholder(1) + " AND table_schema = " + placeholder(2)
+			args = append(args, schemaName)
+		}
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("can not read the columns of table %s: %w", table, err)
+	}
+	defer rows.Close()
+	ts := make(tableSchema)
+	for rows.Next() {
+		var name, declared string
+		if err := rows.Scan(&name, &declared); err != nil {
+			return nil, fmt.Errorf("can not read the columns of table %s: %w", table, err)
+		}
+		ts[name] = sortOf(declared)
+	}
+	return ts, rows.Err()
+}
+
+// dialect returns the placeholder for the n-th value bound to a statement
+// with the driver d, and the SQL for the current schema. Like bindLimit,
+// it tells drivers apart by the name of their type.
+func dialect(d driver.Driver) (placeholder func(n int) string, currentSchema string) {
+	switch reflect.TypeOf(d).String() {
+	case "*pq.Driver", "*stdlib.Driver":
+		return func(n int) string { return "$" + strconv.Itoa(n) }, "current_schema()"
+	case "*mysql.MySQLDriver":
+		return func(int) string { return "?" }, "DATABASE()"
+	case "*mssql.Driver":
+		return func(n int) string { return "@p" + strconv.Itoa(n) }, "SCHEMA_NAME()"
+	}
+	return func(int) string { return "?" }, "current_schema()"
+}
+
+// declaredSort returns the sort of the values of a column of the declared
+// type, as kindSort does for Go kinds, or "" if the type is not known.
+func declaredSort(declared string) string {
+	t := strings.ToUpper(declared)
+	// The same rules as the type affinity of SQLite, with booleans first.
+	switch {
+	case strings.Contains(t, "BOOL"):
+		return "bool"
+	case strings.Contains(t, "INT"):
+		return "integer"
+	case strings.Contains(t, "CHAR"), strings.Contains(t, "CLOB"), strings.Contains(t, "TEXT"):
+		return "string"
+	case strings.Contains(t, "REAL"), strings.Contains(t, "FLOA"), strings.Contains(t, "DOUB"):
+		return "float"
+	}
+	return ""
+}
+
+// dataTypeSort returns the sort of the values of a column of the data_type
+// given by information_schema, or "" if the type is not known. Unlike the
+// declared types of SQLite, these are names of the types of the dialect,
+// so they are matched whole: interval and point are not integers.
+func dataTypeSort(dataType string) string {
+	switch strings.ToLower(dataType) {
+	case "boolean", "bool":
+		return "bool"
+	case "integer", "int", "smallint", "bigint", "tinyint", "mediumint":
+		return "integer"
+	case "character varying", "character", "varchar", "char", "text", "nvarchar",
+		"nchar", "ntext", "tinytext", "mediumtext", "longtext", "citext":
+		return "string"
+	case "real", "double precision", "double", "float":
+		return "float"
+	}
+	return ""
+}
+
+// sortType returns the Go type of the values of
+// the sort colSort, nil if not known.
+func sortType(colSort string) reflect.Type {
+	switch colSort {
+	case "bool":
+		return reflect.TypeOf(false)
+	case "integer":
+		return reflect.TypeOf(int64(0))
+	case "string":
+		return reflect.TypeOf("")
+	case "float":
+		return reflect.TypeOf(float64(0))
+	}
+	return nil
+}
+
+// compatibleKind reports whether a Go value of kind k can hold the values of
+// a column of the sort colSort. Kinds and sorts that are not known, such as
+// structs for times or columns without a type, are taken to be compatible.
+func compatibleKind(k reflect.Kind, colSort string) bool {
+	ks := kindSort(k)
+	switch {
+	case colSort == "" || ks == "" || colSort == ks:
+		return true
+	case colSort == "integer":
+		// SQLite keeps booleans as integers and integers fit in floats.
+		return ks == "bool" || ks == "float"
+	}
+	return false
+}
+
+// isInsert reports whether the statement is an INSERT, which might follow
+// the common table expressions of a WITH clause.
+func isInsert(parts []Part) bool {
+	return statementVerb(statementWords(parts)) == "INSERT"
+}
+
+// returnsRows reports whether the statement has rows without any outputs,
+// as queries and statements with a RETURNING clause do.
+func returnsRows(parts []Part) bool {
+	words := statementWords(parts)
+	switch statementVerb(words) {
+	case "SELECT", "VALUES", "PRAGMA", "EXPLAIN", "SHOW":
+		return true
+	}
+	for _, word := range words {
+		if word == "RETURNING" {
+			return true
+		}
+	}
+	return false
+}
+
+// statementWords returns the words of the statement
+// outside parentheses, in upper case.
+func statementWords(parts []Part) []string {
+	var words []string
+	depth := 0
+	for _, part := range parts {
+		if sp, ok := part.(*stringPart); ok {
+			walkChunk(sp.Chunk, func(word string) {
+				if depth == 0 {
+					words = append(words, strings.ToUpper(word))
+				}
+			}, func(c rune) {
+				switch c {
+				case '(':
+					depth++
+				case ')':
+					depth--
+				}
+			})
+		}
+	}
+	return words
+}
+
+// statementVerb returns the first word of the statement, such as SELECT
+// or INSERT, after the common table expressions of a WITH clause.
+func statementVerb(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	if words[0] != "WITH" {
+		return words[0]
+	}
+	for _, word := range words[1:] {
+		switch word {
+		case "INSERT", "SELECT", "UPDATE", "DELETE":
+			return word
+		}
+	}
+	return ""
+}
+
+// setInsertId writes the id of the row inserted by the statement into the
+// fields tagged "pk" or "autoincrement" of the struct that supplies the row,
+// passed by pointer. That is the struct of every input in the VALUES tuple,
+// as in VALUES ($Person.name, $Person.address_id), or else the struct of the
+// only input of several fields, as in VALUES ($Person.*, $Address.id). The
+// structs of other inputs are only read from and are left as they are.
+// Nothing is written when the driver does not know the id or when the
+// statement did not insert a single row, as with ON CONFLICT DO NOTHING.
+// Bulk inputs insert several rows and are left as they are too.
+func (ce *CompletedExpr) setInsertId(res sql.Result, parts []Part, argTypes typeMap) error {
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return nil
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil
+	}
+	// The inputs of the VALUES tuple and their arguments
+	var inputs []*inputPart
+	var args []any
+	var ai int
+	for _, part := range parts {
+		switch p := part.(type) {
+		case *inputPart:
+			if p.InValues {
+				inputs = append(inputs, p)
+				args = append(args, ce.arguments[ai])
+			}
+			ai++
+		case *outputPart:
+			ai += len(p.TypeNames())
+		}
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+	row := 0        // The input that supplies the row
+	multiField := 0 // The number of inputs of several fields
+	for i, ip := range inputs {
+		if ip.Slice {
+			return nil
+		}
+		if ip.isMultiField() {
+			row = i
+			multiField++
+		}
+	}
+	if multiField != 1 {
+		first := reflect.ValueOf(args[0])
+		for _, arg := range args {
+			v := reflect.ValueOf(arg)
+			if v.Kind() != reflect.Pointer || first.Kind() != reflect.Pointer || v.Pointer() != first.Pointer() {
+				return nil
+			}
+		}
+	}
+	p := inputs[row]
+	v := reflect.ValueOf(args[row])
+	sf, ok := argTypes[p.TypeExpr.Type].(sqlairreflect.Struct)
+	if !ok || v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	for tag, field := range sf.Fields {
+		f := v.Elem().Field(field.Index)
+		if !field.AutoIncrement || !f.CanSet() {
+			continue
+		}
+		if !assignValue(f, id) {
+			fieldName := v.Elem().Type().Field(field.Index).Name
+			return &TypeMismatchError{tag, reflect.TypeOf(id), p.TypeExpr.Type + "." + fieldName, f.Type()}
+		}
+	}
+	return nil
+}
+
+// defaultBindLimit is the number of values that can be bound to a statement
+// with any of the databases that we know of, it is the limit of SQLite
+// before 3.32.0.
+const defaultBindLimit = 999
+
+// bindLimit returns the maximum number of values that can be bound to a
+// single statement with the driver d. Drivers are told apart by the name of
+// their type, so that this package does not depend on all of them.
+func bindLimit(d driver.Driver) int {
+	if _, ok := d.(*sqlite3.SQLiteDriver); ok {
+		// SQLITE_MAX_VARIABLE_NUMBER went up from 999 in SQLite 3.32.0
+		if _, version, _ := sqlite3.Version(); version >= 3032000 {
+			return 32766
+		}
+		return defaultBindLimit
+	}
+	switch reflect.TypeOf(d).String() {
+	case "*pq.Driver", "*stdlib.Driver", "*mysql.MySQLDriver":
+		// PostgreSQL (lib/pq and pgx) and MySQL count the
+		// placeholders of a statement in 16 bits.
+		return 65535
+	case "*mssql.Driver":
+		return 2100
+	}
+	return defaultBindLimit
+}
+
+// AF: outputs are the vars to put the outputs INTO
+// Without outputs, Scan uses the arguments given to Complete for the output
+// expressions. That way the RETURNING clause of
+//
+//	INSERT INTO person (*) VALUES ($Person.*) RETURNING id AS &Person.id
+//
+// fills in the struct that supplied the inputs with Complete(&p, &p).
+func (ce *CompletedExpr) Scan(parts []Part, argTypes typeMap, outputs ...any) error {
+	ce.report = ScanReport{}
+	if ce.rows == nil {
+		return ErrNoResults
+	}
+	defer ce.rows.Close()
+	if len(outputs) == 0 {
+		outputs = ce.outputArguments(parts)
+	}
+	if len(outputs) != len(ce.outputSpecs) {
+		return &ArgumentCountError{"outputs", len(ce.outputSpecs), len(outputs)}
+	}
+	for i, output := range outputs {
+		if reflect.ValueOf(output).Kind() != reflect.Pointer {
+			name := ce.outputSpecs[i].OutputTypeName
+			return &KindError{name, reflect.Pointer, fmt.Sprintf("can not scan into %s, it is not a pointer", name)}
+		}
+	}
+
+	columns, err := ce.rows.Columns()
+	if err != nil {
+		return err
+	}
+	// We should really have a Next method for the whole CompleteExpression interface
+	if !ce.rows.Next() {
+		if err := ce.rows.Err(); err != nil {
+			return err
+		}
+		return ErrNoRows
+	}
+	if err := ce.scanRow(columns, argTypes, outputs); err != nil {
+		return err
+	}
+	return ce.rows.Close()
+}
+
+// scanRow scans the current row, with the given columns, into the outputs.
+// The values go into copies of the outputs, which are written back only if
+// every column could be assigned, so that the outputs are left as they were
+// on errors, including those of ScanStrict.
+func (ce *CompletedExpr) scanRow(columns []string, argTypes typeMap, outputs []any) error {
+	ce.report = ScanReport{}
+	values, err := ce.scanValues(len(columns))
+	if err != nil {
+		return err
+	}
+	colToIndex := map[string]int{}
+	for i, colName := range columns {
+		colToIndex[colName] = i
+	}
+
+	// scanned is an output value, which several specs might fill in, as
+	// in SELECT &Person.name, p.id AS &Person.id with the same *Person.
+	type scanned struct {
+		output   reflect.Value
+		copy     reflect.Value
+		typeName string
+		fields   []string // Those that Scan fills in or leaves untouched
+		filled   map[string]bool
+	}
+	var order []*scanned
+	byOutput := make(map[any]*scanned)
+
+	// The output columns have the aliases _sqlair_0, _sqlair_1... in the
+	// completed SQL, in the order of the output specs.
+	used := make([]bool, len(columns))
+	var n int // The number of the alias of the first column of the spec
+	for i, oi := range ce.outputSpecs {
+		first := n
+		n += len(oi.OutputColumns)
+		sc, ok := byOutput[outputs[i]]
+		if !ok {
+			output := reflect.ValueOf(outputs[i]).Elem()
+			sc = &scanned{output: output, copy: reflect.New(output.Type()).Elem(),
+				typeName: oi.OutputTypeName, filled: map[string]bool{}}
+			sc.copy.Set(output)
+			byOutput[outputs[i]] = sc
+			order = append(order, sc)
+		}
+		s := sc.copy
+		outputStruct, ok := argTypes[oi.OutputTypeName].(sqlairreflect.Struct)
+		if !ok {
+			// COUNT(*) AS &Count
+			sc.fields = []string{""}
+			ci, found := colToIndex[aliasName(first)]
+			if !found {
+				continue
+			}
+			val := values[ci]
+			if !assignValue(s, val) {
+				return &TypeMismatchError{oi.OutputColumns[0], reflect.TypeOf(val), oi.OutputTypeName, s.Type()}
+			}
+			used[ci] = true
+			sc.filled[""] = true
+			continue
+		}
+		if sc.fields == nil {
+			// The fields that &Type.* would fill in
+			sc.fields = outputStruct.Order
+		}
+
+		for j, outputCol := range oi.OutputColumns {
+			// Fields of nested structs have the path to them: Address.city
+			colName, index, found := lookupField(outputStruct, outputCol)
+			if !found {
+				return unknownField(oi.OutputTypeName, outputCol, outputStruct)
+			}
+			ci, found := colToIndex[aliasName(first+j)]
+			if !found {
+				continue
+			}
+			val := values[ci]
+			outputField := s.FieldByIndex(index)
+			fieldName := s.Type().FieldByIndex(index).Name
+			valType := reflect.TypeOf(val)
+			if !outputField.CanSet() {
+				return &ColumnError{[]string{colName},
+					fmt.Sprintf("the field %s of %s is not exported", fieldName, oi.OutputTypeName)}
+			}
+			if !assignValue(outputField, val) {
+				return &TypeMismatchError{colName, valType, oi.OutputTypeName + "." + fieldName, outputField.Type()}
+			}
+			used[ci] = true
+			name := colName
+			if strings.Contains(outputCol, ".") {
+				// A field that &Type.* does not fill in, as &Person.Address.city
+				name = outputCol
+				if !sc.filled[name] {
+					sc.fields = append(sc.fields[:len(sc.fields):len(sc.fields)], name)
+				}
+			}
+			sc.filled[name] = true
+		}
+	}
+
+	for _, sc := range order {
+		for _, name := range sc.fields {
+			fullName := strings.TrimSuffix(sc.typeName+"."+name, ".")
+			if sc.filled[name] {
+				ce.report.Filled = append(ce.report.Filled, fullName)
+			} else {
+				ce.report.Untouched = append(ce.report.Untouched, fullName)
+			}
+		}
+	}
+	if ce.scanMode == ScanStrict {
+		if len(ce.report.Untouched) > 0 {
+			return &ColumnError{ce.report.Untouched,
+				fmt.Sprintf("no column in the results for %s", strings.Join(ce.report.Untouched, ", "))}
+		}
+		for i, colName := range columns {
+			if !used[i] {
+				return &ColumnError{[]string{colName},
+					fmt.Sprintf("column %s of the results goes into no output", colName)}
+			}
+		}
+	}
+	for _, sc := range order {
+		sc.output.Set(sc.copy)
+	}
+	return nil
+}
+
+// scanValues scans the n columns of the current row
+// into values of the types of the driver.
+func (ce *CompletedExpr) scanValues(n int) ([]any, error) {
+	values := make([]interface{}, n)
+	valuePtrs := make([]interface{}, n)
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+	if err := ce.rows.Scan(valuePtrs...); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// ScanMaps returns every row of the results as a map from the name of the
+// column to its value, instead of Scan. The statement can be a query or have
+// outputs or a RETURNING clause. The columns of output expressions have the
+// name of the output and of the column, as Person.name in &Person.name, and
+// not their alias. Text is returned as a string rather than as the []byte of
+// the driver.
+func (ce *CompletedExpr) ScanMaps() ([]map[string]any, error) {
+	if ce.rows == nil {
+		return nil, ErrNoResults
+	}
+	defer ce.rows.Close()
+	columnTypes, err := ce.rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	names, err := ce.columnNames(columnTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []map[string]any
+	for ce.rows.Next() {
+		values, err := ce.scanValues(len(columnTypes))
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(values))
+		for i, val := range values {
+			row[names[i]] = normalizeValue(val, columnTypes[i])
+		}
+		all = append(all, row)
+	}
+	if err := ce.rows.Err(); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// columnNames returns the names of the columns of the results, with the
+// aliases of the output columns replaced by the output and the column, as in
+// Person.name. Two columns of the same name, as in SELECT a.id, b.id, are
+// an error as they would go to the same key of the maps.
+func (ce *CompletedExpr) columnNames(columnTypes []*sql.ColumnType) ([]string, error) {
+	outputColumns := map[string]string{}
+	var n int
+	for _, oi := range ce.outputSpecs {
+		for _, col := range oi.OutputColumns {
+			outputColumns[aliasName(n)] = oi.OutputTypeName + "." + col
+			n++
+		}
+	}
+	names := make([]string, len(columnTypes))
+	seen := make(map[string]bool)
+	for i, ct := range columnTypes {
+		names[i] = ct.Name()
+		if col, ok := outputColumns[ct.Name()]; ok {
+			names[i] = col
+		}
+		if seen[names[i]] {
+			return nil, &ColumnError{[]string{names[i]},
+				fmt.Sprintf("column %s is twice in the results", names[i])}
+		}
+		seen[names[i]] = true
+	}
+	return names, nil
+}
+
+// normalizeValue returns the value val of a column of type ct read by the
+// driver, with []byte turned into a string if the column is text. Columns
+// of other or unknown types, such as expressions, keep their bytes, which
+// are copied as the driver might reuse them.
+func normalizeValue(val any, ct *sql.ColumnType) any {
+	b, ok := val.([]byte)
+	if !ok {
+		return val
+	}
+	if declaredSort(ct.DatabaseTypeName()) == "string" {
+		return string(b)
+	}
+	return append([]byte(nil), b...)
+}
+
+// outputArguments returns the arguments given to Complete
+// for the output expressions in the parts of the statement.
+func (ce *CompletedExpr) outputArguments(parts []Part) []any {
+	var outputs []any
+	var ai int
+	for _, part := range parts {
+		switch p := part.(type) {
+		case *inputPart:
+			ai++
+		case *outputPart:
+			n := len(p.TypeNames())
+			outputs = append(outputs, ce.arguments[ai:ai+n]...)
+			ai += n
+		}
+	}
+	return outputs
+}
+
+// Get runs the prepared statement stmt with the inputs and returns its first
+// row as a T. All the outputs of the statement must be of type T, as in
+//
+//	parsed, err := NewParser().Parse("SELECT &Person.* FROM person WHERE address_id = $Address.id")
+//	stmt, err := parsed.Prepare(Person{}, Address{})
+//	p, err := Get[Person](ctx, db, stmt, addr)
+//
+// It returns ErrNoRows if the statement has no rows.
+func Get[T any](ctx context.Context, db *sql.DB, stmt *PreparedExpr, inputs ...any) (T, error) {
+	var out T
+	q, err := runTyped[T](ctx, db, stmt, inputs)
+	if err != nil {
+		return out, err
+	}
+	err = q.ce.Scan(q.parts, q.argTypes, q.outputs(&out)...)
+	return out, err
+}
+
+// All runs the prepared statement stmt with the inputs, like Get,
+// and returns all of its rows.
+func All[T any](ctx context.Context, db *sql.DB, stmt *PreparedExpr, inputs ...any) ([]T, error) {
+	q, err := runTyped[T](ctx, db, stmt, inputs)
+	if err != nil {
+		return nil, err
+	}
+	rows := q.ce.rows
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var all []T
+	for rows.Next() {
+		var out T
+		if err := q.ce.scanRow(columns, q.argTypes, q.outputs(&out)); err != nil {
+			return nil, err
+		}
+		all = append(all, out)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	return &ce, nil
+	return all, nil
 }
 
-type CompletedExpr struct {
-	sb          strings.Builder
-	arguments   []any
-	outputSpecs []OutputInfo
-	rows        *sql.Rows
+// typedQuery is a statement run by Get or All, with rows to scan.
+type typedQuery struct {
+	ce       *CompletedExpr
+	parts    []Part
+	argTypes typeMap
 }
 
-// add pushes a new piece to the SQL statement that will be ready to be executed
-// in the DB
-func (ce *CompletedExpr) Add(str string) {
-	ce.sb.WriteString(str)
-	ce.sb.WriteString(" ")
+// outputs returns out for every output spec of the statement.
+func (q *typedQuery) outputs(out any) []any {
+	outputs := make([]any, len(q.ce.outputSpecs))
+	for i := range outputs {
+		outputs[i] = out
+	}
+	return outputs
 }
 
-func (ce *CompletedExpr) Sql() string {
-	str := ce.sb.String()
-	if str[len(str)-1] == ' ' {
-		return str[:len(str)-1]
+// runTyped completes and runs the prepared statement of Get and All. The
+// inputs go to the input expressions in order and the outputs must all be
+// of type T.
+func runTyped[T any](ctx context.Context, db *sql.DB, stmt *PreparedExpr, inputs []any) (*typedQuery, error) {
+	var out T
+	outType := reflect.TypeOf(out)
+	parts := stmt.Parsed.parts
+
+	var numInputs, numOutputs int
+	for _, part := range parts {
+		switch p := part.(type) {
+		case *inputPart:
+			numInputs++
+		case *outputPart:
+			for _, name := range p.TypeNames() {
+				// Aliases have the type information of their types.
+				if info, ok := stmt.ArgTypes[name]; !ok || info.Type() != outType {
+					return nil, fmt.Errorf("%w: %s is not a %s", ErrOutputType, name, outType.Name())
+				}
+				numOutputs++
+			}
+		}
+	}
+	if numOutputs == 0 {
+		return nil, fmt.Errorf("%w to go into %s", ErrNoOutputs, outType.Name())
+	}
+	if numInputs != len(inputs) {
+		return nil, &ArgumentCountError{"inputs", numInputs, len(inputs)}
 	}
-	return str
-}
 
-func (ce *CompletedExpr) Exec(db *sql.DB, parts []Part, argTypes typeMap) error {
-	// In order to execute the query, we need to pass the proper arguments
-	// so they can be bound. Get the input parts and pass them one at a
-	// time.
-	var bindArgs []any
-	var pi int // AF: A little confusing. I guess its the counter for which argument we are currently on
+	var args []any
+	var ii int
 	for _, part := range parts {
-		switch part.(type) {
+		switch p := part.(type) {
 		case *inputPart:
-			ip := part.(*inputPart)
-			infstruct := argTypes[ip.TypeExpr.Type]
-			structfield := infstruct.(sqlairreflect.Struct).Fields[ip.TypeExpr.Field]
-			fieldindex := structfield.Index
-			val := reflect.ValueOf(ce.arguments[pi])
-			val = reflect.Indirect(val)
-			if val.Kind() != reflect.Struct {
-				return fmt.Errorf("Can't use as parameter something that is not a struct")
-			}
-			arg := val.Field(fieldindex)
-			bindArgs = append(bindArgs, arg.Interface())
-			pi++
+			args = append(args, inputs[ii])
+			ii++
 		case *outputPart:
-			pi++
+			for range p.TypeNames() {
+				args = append(args, &out)
+			}
 		}
 	}
-	var err error
-	ce.rows, err = db.Query(ce.Sql(), bindArgs...)
+	ce, err := stmt.Complete(args...)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	//Just for printing the rows:
-	//  var name string
-	//  if err := ce.rows.Scan(&name); err != nil {
-	//      return err
-	//  }
-	//fmt.Printf("\nThe rows %+v\n", ce.rows)
-	return nil
+	if err := ce.ExecContext(ctx, db, parts, stmt.ArgTypes); err != nil {
+		return nil, err
+	}
+	return &typedQuery{ce: ce, parts: parts, argTypes: stmt.ArgTypes}, nil
 }
 
-// AF: outputs are the vars to put the outputs INTO
-func (ce *CompletedExpr) Scan(parts []Part, argTypes typeMap, outputs ...any) error {
-
-	columns, _ := ce.rows.Columns()
-	values := make([]interface{}, len(columns))
-	valuePtrs := make([]interface{}, len(columns))
-	colToIndex := map[string]int{}
-	for i, colName := range columns {
-		valuePtrs[i] = &values[i]
-		colToIndex[colName] = i
+// assignValue sets dst to the value val read from the database. A value of
+// another type is converted when both are the same sort of value, so that
+// an int64 column can go into a field of type int or of type Count int.
+// It returns false if val can not go into dst, which includes numbers
+// that do not fit in it.
+func assignValue(dst reflect.Value, val any) bool {
+	v := reflect.ValueOf(val)
+	if !v.IsValid() {
+		return false
 	}
-
-	ce.rows.Next() // We should really have a Next method for the whole CompleteExpression interface
-	ce.rows.Scan(valuePtrs...)
-	ce.rows.Close()
-
-	for i, oi := range ce.outputSpecs {
-		outputStruct := argTypes[oi.OutputTypeName].(sqlairreflect.Struct)
-		s := reflect.ValueOf(outputs[i]).Elem()
-
-		for _, colName := range oi.OutputColumns {
-			field, found := outputStruct.Fields[colName]
-
-			if !found {
-				return fmt.Errorf("can not found column '%s' of output type %s in results", colName, oi.OutputTypeName)
-			}
-			val := values[colToIndex[colName]]
-			findex := field.Index
-			outputField := s.Field(findex)
-			valType := reflect.TypeOf(val)
-			if !outputField.CanSet() {
-				return fmt.Errorf("the field %s of %s is not exported", field.Name, oi.OutputTypeName)
-			}
-			if valType == outputField.Type() {
-				outputField.Set(reflect.ValueOf(val))
-			} else {
-				return fmt.Errorf("the column %s is type %s but the struct %s has type %s", colName, valType, field.Name, outputField.Type())
-			}
+	if v.Type() == dst.Type() {
+		dst.Set(v)
+		return true
+	}
+	if ks := kindSort(v.Kind()); ks != "" && ks == kindSort(dst.Kind()) {
+		if overflows(dst, v) {
+			return false
 		}
+		dst.Set(v.Convert(dst.Type()))
+		return true
 	}
+	return false
+}
 
-	return nil
+// overflows reports whether the number v would be truncated or would wrap
+// around when converted to the type of dst, as 1000 in an int8 or -1 in
+// a uint.
+func overflows(dst, v reflect.Value) bool {
+	switch {
+	case dst.CanInt() && v.CanInt():
+		return dst.OverflowInt(v.Int())
+	case dst.CanInt() && v.CanUint():
+		return v.Uint() > math.MaxInt64 || dst.OverflowInt(int64(v.Uint()))
+	case dst.CanUint() && v.CanInt():
+		return v.Int() < 0 || dst.OverflowUint(uint64(v.Int()))
+	case dst.CanUint() && v.CanUint():
+		return dst.OverflowUint(v.Uint())
+	case dst.CanFloat() && v.CanFloat():
+		return dst.OverflowFloat(v.Float())
+	}
+	return false
+}
+
+// kindSort groups the kinds of values that can be converted into each other.
+func kindSort(k reflect.Kind) string {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	}
+	return ""
 }
 
 // typesForStatement returns reflection information for the input arguments.
-// The reflected type name of each argument must be unique in the list,
-// which means declaring new local types to avoid ambiguity.
+// The reflected type name of each argument must be unique in the list.
+// A type used for several outputs, as in a self join, is given an alias in
+// the statement instead.
 //
 // Example:
 //
 //	type Person struct{}
-//	type Manager Person
 //
 //	stmt, err := sqlair.Prepare(`
 //	SELECT p.* AS &Person.*,
-//		   m.* AS &Manager.*
+//		   m.* AS &Person.* AS boss
 //	  FROM person AS p
 //	  JOIN person AS m
 //		ON p.manager_id = m.id
-//	 WHERE p.name = 'Fred'`, Person{}, Manager{})
+//	 WHERE p.name = 'Fred'`, Person{})
 func typesForStatement(args []any) (typeMap, error) {
 	c := sqlairreflect.Cache()
 	argTypes := make(typeMap)
 	for _, arg := range args {
-		// reflected is some type of the Info interface, right now it'll only be a Struct struct
+		// reflected is a Struct for structs and a Value for any other type
 		reflected, err := c.Reflect(arg)
 		if err != nil {
 			return nil, err
@@ -677,7 +2689,7 @@ func typesForStatement(args []any) (typeMap, error) {
 
 		name := reflected.Name() // Name would be Person
 		if _, ok := argTypes[name]; ok {
-			return nil, fmt.Errorf("type '%s' not unique", name)
+			return nil, fmt.Errorf("%w (%s)", ErrDuplicateType, name)
 		}
 
 		argTypes[name] = reflected
@@ -712,6 +2724,8 @@ type qualifiedName struct {
 
 // tableColumn represents a column qualified by a table.
 // For instance: person.name
+// Column can also hold any other SQL expression used as the source of an
+// output, such as COUNT(*). In that case Table is empty.
 type tableColumn struct {
 	Table  string
 	Column string
@@ -727,27 +2741,237 @@ type typeField struct {
 // TypeMappingExpression describes an expression that
 // is for mapping inputs or outputs to Go types.
 type TypeMappingExpression interface {
-	// TypeName returns the type name used in this expression,
+	// TypeNames returns the type names used in this expression,
 	// such as "Person" in "&Person.*" or "$Person.id".
-	TypeName() string
+	// Column groups like (p.name, a.city) AS (&Person.name, &Address.city)
+	// can use more than one type.
+	TypeNames() []string
 }
 
 // inputPart represents an input expression as specified in the SDL.
 // For instance: $Address.postal_code
 type inputPart struct {
 	TypeExpr typeField
+	// Slice is true when the input expands the elements of a slice,
+	// as in $Ids[:], $Person.ids[:] or $Person[:].*
+	Slice bool
+	// FieldList holds the fields of $Person.{name, age}
+	FieldList []string
+	// Assign is true when the input expands to assignments for the
+	// SET clause of an UPDATE, as in SET $Person.{name, age}
+	Assign bool
+	// InValues is true when the input is in the VALUES of an INSERT,
+	// as in VALUES ($Person.*) or VALUES $Person[:].*
+	InValues bool
+	// InTuple is true when the input is an item of a tuple compared or
+	// assigned to, as in (id, name) = ($Person.{id, name})
+	InTuple bool
+	// NotIn is true when the input is in the list of a NOT IN,
+	// as in id NOT IN ($Ids[:])
+	NotIn bool
 }
 
 func (ip *inputPart) String() string {
-	return "inputPart[" + ip.TypeExpr.Type + "." + ip.TypeExpr.Field + "]"
+	if ip.isBulk() {
+		return "inputPart[" + ip.TypeExpr.Type + "[:].*]"
+	}
+	if len(ip.FieldList) > 0 {
+		return "inputPart[" + ip.TypeExpr.Type + ".{" + strings.Join(ip.FieldList, ", ") + "}]"
+	}
+	out := "inputPart[" + ip.TypeExpr.Type + "." + ip.TypeExpr.Field
+	if ip.Slice {
+		out = out + "[:]"
+	}
+	return out + "]"
+}
+
+// expression returns the input as it is written in the statement.
+func (ip *inputPart) expression() string {
+	switch {
+	case ip.isBulk():
+		return "$" + ip.TypeExpr.Type + "[:].*"
+	case len(ip.FieldList) > 0:
+		return "$" + ip.TypeExpr.Type + ".{" + strings.Join(ip.FieldList, ", ") + "}"
+	}
+	expr := "$" + ip.TypeExpr.Type
+	if ip.TypeExpr.Field != "" {
+		expr += "." + ip.TypeExpr.Field
+	}
+	if ip.Slice {
+		expr += "[:]"
+	}
+	return expr
+}
+
+func (ip *inputPart) TypeNames() []string {
+	return []string{ip.TypeExpr.Type}
+}
+
+// isMultiField reports whether the input is for several fields of a struct
+// as in $Person.* or $Person.{name, age}
+func (ip *inputPart) isMultiField() bool {
+	return ip.TypeExpr.Field == "*" || len(ip.FieldList) > 0
+}
+
+// tags returns the tags of the fields of a multi field input, all of them
+// for $Type.* and the ones listed for $Type.{a, b}.
+func (ip *inputPart) tags(sf sqlairreflect.Struct) ([]string, error) {
+	if ip.TypeExpr.Field == "*" {
+		return sf.Order, nil
+	}
+	tags := make([]string, 0, len(ip.FieldList))
+	for _, name := range ip.FieldList {
+		tag, found := fieldTag(sf, name)
+		if !found {
+			return nil, unknownField(ip.TypeExpr.Type, name, sf)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// isBulk reports whether the input is a slice of structs that expands
+// to one VALUES row per element, as in $Person[:].*
+func (ip *inputPart) isBulk() bool {
+	return ip.Slice && ip.TypeExpr.Field == "*"
+}
+
+// row returns the placeholders for one VALUES row of a bulk input and
+// the number of values bound to them: $Person[:].* --> (?, ?, ?), 3
+func (ip *inputPart) row(argTypes typeMap) (string, int) {
+	n := len(argTypes[ip.TypeExpr.Type].(sqlairreflect.Struct).Fields)
+	return "(" + placeholders(n) + ")", n
+}
+
+// ToSql returns the placeholders of the input for the argument arg.
+// Slices get one placeholder per element: $Ids[:] --> ?, ?, ?
+// structs one per tagged field: $Person.* --> ?, ?, ?
+// and slices of structs one row per element: $Person[:].* --> (?, ?), (?, ?)
+// In a SET clause there is an assignment per field: $Person.{a, b} --> a = ?, b = ?
+func (ip *inputPart) ToSql(arg any, argTypes typeMap) (string, error) {
+	if !ip.Slice && !ip.isMultiField() {
+		return "?", nil
+	}
+	if ip.Assign {
+		sf, ok := argTypes[ip.TypeExpr.Type].(sqlairreflect.Struct)
+		if !ok {
+			return "", notAStruct(ip.TypeExpr.Type)
+		}
+		tags, err := ip.tags(sf)
+		if err != nil {
+			return "", err
+		}
+		assignments := make([]string, len(tags))
+		for i, tag := range tags {
+			assignments[i] = tag + " = ?"
+		}
+		return strings.Join(assignments, ", "), nil
+	}
+	values, err := ip.bindValues(arg, argTypes)
+	if err != nil {
+		return "", err
+	}
+	if ip.isBulk() {
+		row, n := ip.row(argTypes)
+		if len(values) == 0 || n == 0 {
+			return "", fmt.Errorf("%w for %s[:].*", ErrNoInputRows, ip.TypeExpr.Type)
+		}
+		return strings.TrimSuffix(strings.Repeat(row+", ", len(values)/n), ", "), nil
+	}
+	// IN () is not valid SQL, but IN (NULL) is and it matches nothing.
+	if len(values) == 0 {
+		if ip.NotIn {
+			return "", fmt.Errorf("%w: %s in a NOT IN list", ErrEmptySlice, ip.expression())
+		}
+		return "NULL", nil
+	}
+	return placeholders(len(values)), nil
+}
+
+// placeholders returns a list of n placeholders: ?, ?, ?
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// bindValues returns the values of the argument arg that are bound to the
+// placeholders of the input. That is the value of a struct field, the values
+// of all the tagged fields for $Type.* or, for slices, the elements of the
+// slice.
+func (ip *inputPart) bindValues(arg any, argTypes typeMap) ([]any, error) {
+	val := reflect.Indirect(reflect.ValueOf(arg))
+	if ip.isBulk() {
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			return nil, notASlice(ip.TypeExpr.Type + "[:].*")
+		}
+		sf := argTypes[ip.TypeExpr.Type].(sqlairreflect.Struct)
+		var values []any
+		for i := 0; i < val.Len(); i++ {
+			row := reflect.Indirect(val.Index(i))
+			if row.Kind() != reflect.Struct {
+				return nil, notAStruct(ip.TypeExpr.Type)
+			}
+			values = append(values, structValues(row, sf)...)
+		}
+		return values, nil
+	}
+	// Types that are not structs are bound as they are: $PersonID
+	_, isStruct := argTypes[ip.TypeExpr.Type].(sqlairreflect.Struct)
+	if isStruct && (!ip.Slice || ip.TypeExpr.Field != "") {
+		if val.Kind() != reflect.Struct {
+			return nil, notAStruct(ip.TypeExpr.Type)
+		}
+		sf := argTypes[ip.TypeExpr.Type].(sqlairreflect.Struct)
+		if ip.isMultiField() {
+			tags, err := ip.tags(sf)
+			if err != nil {
+				return nil, err
+			}
+			values := make([]any, len(tags))
+			for i, tag := range tags {
+				values[i] = val.Field(sf.Fields[tag].Index).Interface()
+			}
+			return values, nil
+		}
+		_, index, found := lookupField(sf, ip.TypeExpr.Field)
+		if !found {
+			return nil, unknownField(ip.TypeExpr.Type, ip.TypeExpr.Field, sf)
+		}
+		val = val.FieldByIndex(index)
+	}
+	if !ip.Slice {
+		return []any{val.Interface()}, nil
+	}
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		name := strings.TrimSuffix(ip.TypeExpr.Type+"."+ip.TypeExpr.Field, ".")
+		return nil, notASlice(name)
+	}
+	values := make([]any, val.Len())
+	for i := range values {
+		values[i] = val.Index(i).Interface()
+	}
+	return values, nil
+}
+
+// notAStruct returns a *KindError for an argument
+// of the type name that is not a struct.
+func notAStruct(name string) error {
+	return &KindError{name, reflect.Struct, "Can't use as parameter something that is not a struct"}
 }
 
-func (ip *inputPart) TypeName() string {
-	return ip.TypeExpr.Type
+// notASlice returns a *KindError for the
+// input name whose value is not a slice.
+func notASlice(name string) error {
+	return &KindError{name, reflect.Slice, fmt.Sprintf("can not expand %s, it is not a slice", name)}
 }
 
-func (ip *inputPart) ToSql() (string, error) {
-	return "?", nil
+// structValues returns the values of all the tagged fields of the struct val
+// in the order in which the fields are declared.
+func structValues(val reflect.Value, sf sqlairreflect.Struct) []any {
+	var values []any
+	for _, tag := range sf.Order {
+		values = append(values, val.Field(sf.Fields[tag].Index).Interface())
+	}
+	return values
 }
 
 // These names are horrible
@@ -790,62 +3014,252 @@ func (op *outputPart) printFields() string {
 	return out
 }
 
-func (op *outputPart) TypeName() string {
-	// FIXME: do we need multiple fields?
-	return op.Fields[0].Type
+// TypeNames returns the distinct types targeted by the output
+// in the order they first appear.
+func (op *outputPart) TypeNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, f := range op.Fields {
+		if !seen[f.Type] {
+			seen[f.Type] = true
+			names = append(names, f.Type)
+		}
+	}
+	return names
+}
+
+// isFieldList reports whether the output goes to a list of fields of a
+// type, as in &Person.{id, name} or p.* AS &Person.{id, name}
+func (op *outputPart) isFieldList() bool {
+	return len(op.Fields) > 1 && len(op.Columns) <= 1
+}
+
+// fieldListTags returns the tags of the fields in the list of fields
+// of the output, in the order they are written.
+func (op *outputPart) fieldListTags(sf sqlairreflect.Struct) ([]string, error) {
+	var tags []string
+	for _, f := range op.Fields {
+		tag, found := fieldTag(sf, f.Field)
+		if !found {
+			return nil, unknownField(f.Type, f.Field, sf)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// columnTag returns the tag of the field that the i-th column of the output
+// goes to, as in full_name AS &Person.name or in the pairs of
+// (p.name, a.city) AS (&Person.name, &Address.city).
+// It returns "" when the column does not go to a single field, as in
+// p.* AS &Person.* or (a.district, a.street) AS &Address.*.
+func (op *outputPart) columnTag(i int, argTypes typeMap) string {
+	if len(op.Fields) != len(op.Columns) || op.Columns[i].Column == "*" {
+		return ""
+	}
+	sf, ok := argTypes[op.Fields[i].Type].(sqlairreflect.Struct)
+	if !ok {
+		return scalarColumn(op.Fields[i].Type)
+	}
+	tag, _ := fieldTag(sf, op.Fields[i].Field)
+	return tag
+}
+
+// fieldTag returns the "db" tag of the field name in the DSL. name can be
+// either the tag itself or the name of the Go field. It returns false if name
+// is "*", empty or not a tagged field of the struct.
+func fieldTag(sf sqlairreflect.Struct, name string) (string, bool) {
+	tag, _, found := lookupField(sf, name)
+	return tag, found
+}
+
+// lookupField finds the field name of the DSL in the struct sf. name is the
+// tag or the Go name of a field, or a path to a field in nested structs, as
+// in Address.city. It returns the tag of the field and the sequence of
+// indexes to get to it with reflect.Value.FieldByIndex.
+func lookupField(sf sqlairreflect.Struct, name string) (string, []int, bool) {
+	var index []int
+	path := strings.Split(name, ".")
+	for _, step := range path[:len(path)-1] {
+		nested, found := sf.Nested[step]
+		if !found {
+			return "", nil, false
+		}
+		index = append(index, nested.Index)
+		sf = nested.Struct
+	}
+	name = path[len(path)-1]
+	if _, found := sf.Fields[name]; !found {
+		tag, found := sf.Tags[name]
+		if !found {
+			return "", nil, false
+		}
+		name = tag
+	}
+	return name, append(index, sf.Fields[name].Index), true
 }
 
-func (op *outputPart) ToSql(pe *PreparedExpr) (string, error) {
+func (op *outputPart) ToSql(pe *PreparedExpr, spec int) (string, error) {
 	// The &Type.Field syntax is part of the DSL but not SQL so we can not
 	// print that. We do need to print the columns though (if any)
-	// There are two cases here
-	var out string
-	sf := pe.ArgTypes[op.TypeName()].(sqlairreflect.Struct)
+	// Every column that goes to a field gets a unique alias, such as
+	// _sqlair_0, so that Scan can tell apart columns with the same name
+	// from different tables. spec is the index of the output spec of the
+	// first type of the output.
+	// The columns are printed in the order of the output columns of the
+	// specs, so the next one of each spec gets the next alias. Columns that
+	// do not go to a field, as foo in (foo, a.id) AS &Address.*, get none.
+	var columns []string
+	names := op.TypeNames()
+	next := make([]int, len(names)) // The next output column of each spec
+	as := func(column, typeName, outputCol string) {
+		for i, name := range names {
+			if name != typeName {
+				continue
+			}
+			cols := pe.OutputSpecs[spec+i].OutputColumns
+			if k := next[i]; k < len(cols) && cols[k] == outputCol {
+				column = column + " AS " + pe.columnAlias(spec+i, k)
+				next[i]++
+			}
+		}
+		columns = append(columns, column)
+	}
+	// There are three cases here
+	if op.isFieldList() {
+		// &Person.{id, name} --> id AS _sqlair_0, name AS _sqlair_1
+		// p.* AS &Person.{id, name} --> p.id AS _sqlair_0, p.name AS _sqlair_1
+		typeName := op.Fields[0].Type
+		sf := pe.ArgTypes[typeName].(sqlairreflect.Struct)
+		var table string
+		if len(op.Columns) == 1 {
+			table = op.Columns[0].Table
+		}
+		tags, err := op.fieldListTags(sf)
+		if err != nil {
+			return "", err
+		}
+		for _, tag := range tags {
+			as(qualified(table, tag), typeName, tag)
+		}
+		return strings.Join(columns, ", "), nil
+	}
 	if len(op.Columns) != 0 {
 		// Case 1
-		// foo as &Type.Field --> print foo
+		// foo as &Type.Field --> print foo AS _sqlair_0
+		// p.* AS &Type.* --> print p.tag1 AS _sqlair_0, p.tag2 AS _sqlair_1,
+		// ... for the tagged fields only, so that the results are what the
+		// struct expects.
 		for i, c := range op.Columns {
-			if i > 0 {
-				out = out + ", "
+			typeName := op.Fields[0].Type
+			if len(op.Fields) == len(op.Columns) {
+				typeName = op.Fields[i].Type
 			}
-			if c.Table != "" {
-				out = out + c.Table + "."
+			if c.Column == "*" {
+				if sf, ok := pe.ArgTypes[typeName].(sqlairreflect.Struct); ok && len(sf.Order) > 0 {
+					for _, tag := range sf.Order {
+						as(qualified(c.Table, tag), typeName, tag)
+					}
+					continue
+				}
 			}
-			out = out + c.Column
-
+			// A column going to a single field is the output of the field,
+			// otherwise the column goes to the field with its name, if any:
+			// (a.district, a.street) AS &Address.*
+			outputCol := c.Column
+			if tag := op.columnTag(i, pe.ArgTypes); tag != "" {
+				outputCol = outputColumn(op.Fields[i].Field, tag)
+			}
+			as(qualified(c.Table, c.Column), typeName, outputCol)
 		}
-		return out, nil
+		return strings.Join(columns, ", "), nil
 	}
 
 	// Case 2: No AS just the Go Struct
 	// &Type.colum --> expand to the name of the column with `db` tag.
+	typeName := op.Fields[0].Type
+	sf := pe.ArgTypes[typeName].(sqlairreflect.Struct)
 	if op.Fields[0].Field != "*" && op.Fields[0].Field != "" {
-		if dbName, found := sf.Tags[op.Fields[0].Field]; found {
-			return dbName, nil
+		if dbName, found := fieldTag(sf, op.Fields[0].Field); found {
+			as(dbName, typeName, outputColumn(op.Fields[0].Field, dbName))
+			return columns[0], nil
 		} else {
-			return "", fmt.Errorf("%s not found", dbName) // Look for the tag
+			return "", unknownField(typeName, op.Fields[0].Field, sf)
 		}
 	}
 
-	tagList := make([]string, 0)
-	for _, tag := range sf.Tags {
-		tagList = append(tagList, tag)
+	// if the column is '*' or there is no column (as in &Person) expand to
+	// all the columns with a `db` tag, in the order of the struct fields.
+	// Ignore the rest.
+	for _, tag := range sf.Order {
+		as(tag, typeName, tag)
+	}
+	return strings.Join(columns, ", "), nil
+}
+
+// qualified returns the column qualified by the table, if there is one: p.id
+func qualified(table, column string) string {
+	if table == "" {
+		return column
 	}
-	// We need this because the order is random every time and the tests depend on it
-	sort.Strings(tagList)
-	for _, tag := range tagList {
+	return table + "." + column
+}
 
-		out = out + tag + ", "
+// columnAlias returns the alias in the completed SQL of the k-th output
+// column of the i-th output spec. The output columns of all the specs are
+// numbered in order.
+func (pe *PreparedExpr) columnAlias(i, k int) string {
+	n := k
+	for _, oi := range pe.OutputSpecs[:i] {
+		n += len(oi.OutputColumns)
 	}
-	out = out[:len(out)-2]
+	return aliasName(n)
+}
 
-	// if the column is '*' or there is no column (as in &Person) expand to
-	// all the columns with a `db` tag. Ignore the rest.
-	// The iteration order of the hash is not specified. We need
-	// to use the same order to be able to write tests that do not fail
-	// randomly.
+// aliasName returns the n-th alias for output columns: _sqlair_n
+func aliasName(n int) string {
+	return "_sqlair_" + strconv.Itoa(n)
+}
+
+// insertColumnsPart represents the (*) column list of an INSERT statement.
+// For instance: INSERT INTO person (*) VALUES ($Person.*)
+// It expands to the columns of the inputs in the VALUES tuple that follows it.
+type insertColumnsPart struct {
+	Inputs []*inputPart
+}
+
+func (ic *insertColumnsPart) String() string {
+	return "insertColumnsPart[(*)]"
+}
 
-	return out, nil
+// ToSql returns the column list for the inputs of the VALUES tuple,
+// all the tagged columns for $Type.* and the tag for $Type.field:
+// (*) VALUES ($Person.*, $Address.id) --> (id, name, address_id, id)
+func (ic *insertColumnsPart) ToSql(argTypes typeMap) (string, error) {
+	var columns []string
+	for _, ip := range ic.Inputs {
+		sf, ok := argTypes[ip.TypeExpr.Type].(sqlairreflect.Struct)
+		if !ok || (ip.Slice && !ip.isBulk()) {
+			return "", &InputError{ip.expression(), fmt.Sprintf("can not get columns for %s", ip.expression())}
+		}
+		if ip.isMultiField() {
+			tags, err := ip.tags(sf)
+			if err != nil {
+				return "", err
+			}
+			columns = append(columns, tags...)
+			continue
+		}
+		tag, found := fieldTag(sf, ip.TypeExpr.Field)
+		if !found {
+			return "", unknownField(ip.TypeExpr.Type, ip.TypeExpr.Field, sf)
+		}
+		columns = append(columns, tag)
+	}
+	if len(columns) == 0 {
+		return "", &InputError{"", "no inputs for the (*) column list"}
+	}
+	return "(" + strings.Join(columns, ", ") + ")", nil
 }
 
 func (tf *typeField) String() string {
@@ -871,6 +3285,13 @@ func (p *Parser) init(str string) {
 	p.skipped = 0
 	p.str = str
 	p.parts = nil
+	p.aliases = nil
+	p.tables = nil
+}
+
+// errorf returns a *ParseError at the current position of the parser.
+func (p *Parser) errorf(format string, args ...any) error {
+	return &ParseError{Offset: p.skipped, Msg: fmt.Sprintf(format, args...)}
 }
 
 // addTail adds the remaining part of the SQL statement to be processed
@@ -882,7 +3303,7 @@ func (p *Parser) addTail() {
 func (p *Parser) Parse(str string) (*ParsedExpr, error) {
 	p.init(str)
 	if p.str == "" {
-		return nil, fmt.Errorf("empty statement")
+		return nil, &ParseError{0, "empty statement"}
 	}
 	// FIXME:
 	// This logic seems weird as it gives the impression that
@@ -896,14 +3317,240 @@ func (p *Parser) Parse(str string) (*ParsedExpr, error) {
 		if err := p.parseOutputExpression(); err != nil {
 			return nil, err
 		}
-		p.parseColumnGroup()
+		p.parseInsertColumns()
+		if err := p.parseColumnGroup(); err != nil {
+			return nil, err
+		}
 		if err := p.parseStringLiteral(); err != nil {
 			return nil, err
 		}
-		p.advance()
+		p.parseCommonTableName()
+		// Whatever follows the tables is left for the next round.
+		if !p.parseTableReference() {
+			p.advance()
+		}
 	}
 	p.addTail()
-	return &ParsedExpr{parts: p.parts}, nil
+	linkInsertColumns(p.parts)
+	markValuesInputs(p.parts)
+	markSetInputs(p.parts)
+	markNotInInputs(p.parts)
+	markTupleInputs(p.parts)
+	return &ParsedExpr{parts: p.parts, aliases: p.aliases, tables: p.tables}, nil
+}
+
+// linkInsertColumns gives every (*) column list of an INSERT statement the
+// inputs of the parenthesised VALUES tuple that follows it.
+func linkInsertColumns(parts []Part) {
+	for i, part := range parts {
+		ic, ok := part.(*insertColumnsPart)
+		if !ok {
+			continue
+		}
+		depth := 0
+	tuple:
+		for _, next := range parts[i+1:] {
+			switch next := next.(type) {
+			case *stringPart:
+				// Parentheses in string literals do not count.
+				if chunk := strings.TrimLeft(next.Chunk, " "); chunk != "" &&
+					(chunk[0] == '\'' || chunk[0] == '"') {
+					continue
+				}
+				for _, c := range next.Chunk {
+					switch c {
+					case '(':
+						depth++
+					case ')':
+						depth--
+						if depth <= 0 {
+							break tuple
+						}
+					}
+				}
+			case *inputPart:
+				// The rows of a bulk input are not in parentheses:
+				// INSERT INTO person (*) VALUES $Person[:].*
+				if depth == 0 && next.isBulk() {
+					ic.Inputs = append(ic.Inputs, next)
+					break tuple
+				}
+				if depth > 0 {
+					ic.Inputs = append(ic.Inputs, next)
+				}
+			case *insertColumnsPart:
+				break tuple
+			}
+		}
+	}
+}
+
+// markValuesInputs sets InValues for the inputs in the parenthesised VALUES
+// tuples of an INSERT statement, and for bulk inputs right after VALUES.
+// Bulk inputs expand to their own tuples, so they are not marked in a tuple.
+func markValuesInputs(parts []Part) {
+	afterValues := false // In the list of tuples that follows VALUES
+	depth := 0
+	for _, part := range parts {
+		switch part := part.(type) {
+		case *stringPart:
+			walkChunk(part.Chunk, func(word string) {
+				if strings.EqualFold(word, "VALUES") {
+					afterValues, depth = true, 0
+				} else if depth == 0 {
+					afterValues = false
+				}
+			}, func(c rune) {
+				switch {
+				case c == '(' && afterValues:
+					depth++
+				case c == ')' && depth > 0:
+					depth--
+				case depth == 0 && c != ',':
+					afterValues = false
+				}
+			})
+		case *inputPart:
+			if afterValues && (depth > 0) != part.isBulk() {
+				part.InValues = true
+			}
+		}
+	}
+}
+
+// markNotInInputs sets NotIn for the inputs in the
+// parenthesised list of a NOT IN, as in id NOT IN (1, $Ids[:])
+func markNotInInputs(parts []Part) {
+	var prev, last string // The last two words
+	var lists []bool      // For every open parenthesis, whether it is a NOT IN list
+	for _, part := range parts {
+		switch part := part.(type) {
+		case *stringPart:
+			walkChunk(part.Chunk, func(word string) {
+				prev, last = last, strings.ToUpper(word)
+			}, func(c rune) {
+				switch c {
+				case '(':
+					lists = append(lists, prev == "NOT" && last == "IN")
+				case ')':
+					if len(lists) > 0 {
+						lists = lists[:len(lists)-1]
+					}
+				}
+				prev, last = "", ""
+			})
+		case *inputPart:
+			if len(lists) > 0 && lists[len(lists)-1] {
+				part.NotIn = true
+			}
+			prev, last = "", ""
+		}
+	}
+}
+
+// markTupleInputs sets InTuple for the inputs that are items of a tuple
+// opened right after = or IN, as in SET (name, age) = ($Person.{name, age})
+// or WHERE (id, name) IN (($Person.{id, name}), (1, 'x')).
+func markTupleInputs(parts []Part) {
+	var last string   // The last word or punctuation
+	var tuples []bool // For every open parenthesis, whether it is a tuple
+	for _, part := range parts {
+		switch part := part.(type) {
+		case *stringPart:
+			walkChunk(part.Chunk, func(word string) {
+				last = strings.ToUpper(word)
+			}, func(c rune) {
+				switch c {
+				case '(':
+					inTuple := len(tuples) > 0 && tuples[len(tuples)-1]
+					tuples = append(tuples, last == "=" || last == "IN" ||
+						inTuple && (last == "(" || last == ","))
+				case ')':
+					if len(tuples) > 0 {
+						tuples = tuples[:len(tuples)-1]
+					}
+				}
+				last = string(c)
+			})
+		case *inputPart:
+			inTuple := len(tuples) > 0 && tuples[len(tuples)-1]
+			if inTuple && (last == "(" || last == ",") && !part.Slice {
+				part.InTuple = true
+			}
+			last = ""
+		default:
+			last = ""
+		}
+	}
+}
+
+// markSetInputs sets Assign for the inputs for several fields that are
+// items of the SET clause of an UPDATE, as in SET x = 1, $Person.{name, age}
+// These expand to assignments: name = ?, age = ?
+// The clause ends at WHERE, FROM or RETURNING.
+func markSetInputs(parts []Part) {
+	inSet := false
+	item := false // At the start of an item of the SET clause
+	depth := 0
+	for _, part := range parts {
+		switch part := part.(type) {
+		case *stringPart:
+			walkChunk(part.Chunk, func(word string) {
+				switch strings.ToUpper(word) {
+				case "SET":
+					inSet, item, depth = true, true, 0
+				case "WHERE", "FROM", "RETURNING":
+					if depth == 0 {
+						inSet = false
+					}
+					item = false
+				default:
+					item = false
+				}
+			}, func(c rune) {
+				switch c {
+				case '(':
+					depth++
+				case ')':
+					depth--
+				case ';':
+					inSet = false
+				}
+				item = inSet && depth == 0 && c == ','
+			})
+		case *inputPart:
+			if item && part.isMultiField() && !part.Slice {
+				part.Assign = true
+			}
+			item = false
+		default:
+			item = false
+		}
+	}
+}
+
+// walkChunk calls word for each word of the SQL chunk and punct for each
+// rune that is neither part of a word nor a space. Chunks that are string
+// literals are skipped, the parentheses and keywords in them do not count.
+func walkChunk(chunk string, word func(string), punct func(rune)) {
+	if trimmed := strings.TrimLeft(chunk, " "); trimmed != "" &&
+		(trimmed[0] == '\'' || trimmed[0] == '"') {
+		return
+	}
+	var w strings.Builder
+	for _, c := range chunk + " " {
+		if isNameRune(c) {
+			w.WriteRune(c)
+			continue
+		}
+		if w.Len() > 0 {
+			word(w.String())
+			w.Reset()
+		}
+		if !unicode.IsSpace(c) {
+			punct(c)
+		}
+	}
 }
 
 func createDb() (*sql.DB, error) {
@@ -944,7 +3591,6 @@ func main() {
 	}
 
 	var manager Person
-	var otherguy Person
 	q := "select p.* as &Person.* from citizens AS p"
 	//q := "SELECT (a.district, a.street) AS &Address.* FROM address AS a WHERE p.name = 'Fred'"
 	//q := "select * as &Person.* from citizens"
@@ -967,11 +3613,11 @@ func main() {
 					fmt.Println(err)
 					return
 				}
-				if err := completedexpr.Scan(parsedexp.parts, preparedexp.ArgTypes, &manager, &otherguy); err != nil {
+				if err := completedexpr.Scan(parsedexp.parts, preparedexp.ArgTypes, &manager); err != nil {
 					fmt.Println(err)
 					return
 				}
-				fmt.Printf("Result: manager - %+v, other guy - %+v", manager, otherguy)
+				fmt.Printf("Result: manager - %+v", manager)
 			} else {
 				fmt.Printf("error completing query: %s", err)
 			}
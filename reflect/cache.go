@@ -25,8 +25,12 @@ func (r *cache) Reflect(value any) (Info, error) {
 	}
 
 	v := reflect.ValueOf(value)
-	if v.IsNil() {
-		return Struct{}, fmt.Errorf("Can not reflect nil value")
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		// Only these kinds can be nil, IsNil panics for the rest.
+		if v.IsNil() {
+			return Struct{}, fmt.Errorf("Can not reflect nil value")
+		}
 	}
 	v = reflect.Indirect(v)
 
@@ -61,6 +65,7 @@ func generate(value reflect.Value) (Info, error) {
 	info := Struct{
 		Fields: make(map[string]Field),
 		Tags:   make(map[string]string),
+		Nested: make(map[string]Nested),
 		value:  value,
 	}
 
@@ -70,38 +75,57 @@ func generate(value reflect.Value) (Info, error) {
 		// Fields without a "db" tag are outside of Sqlair's remit.
 		tag := field.Tag.Get("db")
 		if tag == "" {
+			// Untagged structs, such as an embedded Address,
+			// can hold tagged fields of their own.
+			if field.Type.Kind() == reflect.Struct && field.IsExported() {
+				nested, err := generate(value.Field(i))
+				if err != nil {
+					return Value{}, err
+				}
+				info.Nested[field.Name] = Nested{
+					Field:  Field{Name: field.Name, Index: i, value: value.Field(i)},
+					Struct: nested.(Struct),
+				}
+			}
 			continue
 		}
 
-		tag, omitEmpty, err := parseTag(tag)
+		tag, omitEmpty, autoIncrement, err := parseTag(tag)
 		if err != nil {
 			return Value{}, err
 		}
 
 		info.Fields[tag] = Field{
-			Name:      field.Name,
-			Index:     i,
-			OmitEmpty: omitEmpty,
-			value:     value.Field(i),
+			Name:          field.Name,
+			Index:         i,
+			OmitEmpty:     omitEmpty,
+			AutoIncrement: autoIncrement,
+			value:         value.Field(i),
 		}
 		info.Tags[field.Name] = tag
+		info.Order = append(info.Order, tag)
 	}
 
 	return info, nil
 }
 
-// parseTag parses the input tag string and returns its
-// name and whether it contains the "omitempty" option.
-func parseTag(tag string) (string, bool, error) {
+// parseTag parses the input tag string and returns its name, whether it
+// contains the "omitempty" option and whether it contains the "pk" or
+// "autoincrement" option.
+func parseTag(tag string) (string, bool, bool, error) {
 	options := strings.Split(tag, ",")
 
-	var omitEmpty bool
-	if len(options) > 1 {
-		if strings.ToLower(options[1]) != "omitempty" {
-			return "", false, errors.Errorf("unexpected tag value %q", options[1])
+	var omitEmpty, autoIncrement bool
+	for _, option := range options[1:] {
+		switch strings.ToLower(option) {
+		case "omitempty":
+			omitEmpty = true
+		case "pk", "autoincrement":
+			autoIncrement = true
+		default:
+			return "", false, false, errors.Errorf("unexpected tag value %q", option)
 		}
-		omitEmpty = true
 	}
 
-	return options[0], omitEmpty, nil
+	return options[0], omitEmpty, autoIncrement, nil
 }
@@ -28,6 +28,7 @@ func TestReflectSimpleConcurrent(t *testing.T) {
 
 	assert.Equal(t, reflect.Int64, info.Kind())
 	assert.Equal(t, "int64", info.Name())
+	assert.Equal(t, reflect.TypeOf(num), info.Type())
 
 	_, ok := info.(Value)
 	assert.True(t, ok)
@@ -53,6 +54,7 @@ func TestReflectStruct(t *testing.T) {
 
 	assert.Equal(t, reflect.Struct, info.Kind())
 	assert.Equal(t, "something", info.Name())
+	assert.Equal(t, reflect.TypeOf(s), info.Type())
 
 	st, ok := info.(Struct)
 	assert.True(t, ok)
@@ -68,6 +70,10 @@ func TestReflectStruct(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "Name", name.Name)
 	assert.True(t, name.OmitEmpty)
+	assert.Equal(t, reflect.String, name.Kind())
+	assert.Equal(t, reflect.TypeOf(""), name.Type())
+
+	assert.Equal(t, []string{"id", "name"}, st.Order)
 }
 
 func TestReflectBadTagError(t *testing.T) {
@@ -80,3 +86,52 @@ func TestReflectBadTagError(t *testing.T) {
 	_, err := Cache().Reflect(s)
 	assert.Error(t, errors.New(`unexpected tag value "bad-juju"`), err)
 }
+
+func TestReflectNestedStruct(t *testing.T) {
+	type address struct {
+		City string `db:"city"`
+	}
+	type something struct {
+		ID      int64 `db:"id"`
+		Address address
+		hidden  address
+	}
+
+	info, err := Cache().Reflect(something{})
+	assert.Nil(t, err)
+
+	st, ok := info.(Struct)
+	assert.True(t, ok)
+
+	assert.Len(t, st.Fields, 1)
+	assert.Len(t, st.Nested, 1)
+
+	nested, ok := st.Nested["Address"]
+	assert.True(t, ok)
+	assert.Equal(t, 1, nested.Index)
+	assert.Equal(t, "address", nested.Struct.Name())
+
+	city, ok := nested.Struct.Fields["city"]
+	assert.True(t, ok)
+	assert.Equal(t, "City", city.Name)
+}
+
+func TestReflectAutoIncrement(t *testing.T) {
+	type something struct {
+		ID   int64  `db:"id,pk"`
+		Seq  int64  `db:"seq,omitempty,autoincrement"`
+		Name string `db:"name"`
+	}
+
+	info, err := Cache().Reflect(something{})
+	assert.Nil(t, err)
+
+	st, ok := info.(Struct)
+	assert.True(t, ok)
+
+	assert.True(t, st.Fields["id"].AutoIncrement)
+	assert.False(t, st.Fields["id"].OmitEmpty)
+	assert.True(t, st.Fields["seq"].AutoIncrement)
+	assert.True(t, st.Fields["seq"].OmitEmpty)
+	assert.False(t, st.Fields["name"].AutoIncrement)
+}
@@ -8,6 +8,7 @@ import (
 type Info interface {
 	Name() string
 	Kind() reflect.Kind
+	Type() reflect.Type
 }
 
 // Value represents reflection information for a simple type.
@@ -26,6 +27,11 @@ func (r Value) Name() string {
 	return r.value.Type().Name()
 }
 
+// Type returns the Value's reflect.Type.
+func (r Value) Type() reflect.Type {
+	return r.value.Type()
+}
+
 // Field represents a single field from a struct type.
 type Field struct {
 	value reflect.Value
@@ -39,6 +45,20 @@ type Field struct {
 	// OmitEmpty is true when "omitempty" is
 	// a property of the field's "db" tag.
 	OmitEmpty bool
+
+	// AutoIncrement is true when "pk" or "autoincrement" is a property
+	// of the field's "db" tag. The field gets the id of inserted rows.
+	AutoIncrement bool
+}
+
+// Kind returns the Field's reflect.Kind.
+func (f Field) Kind() reflect.Kind {
+	return f.value.Kind()
+}
+
+// Type returns the Field's reflect.Type.
+func (f Field) Type() reflect.Type {
+	return f.value.Type()
 }
 
 // Struct represents reflected information about a struct type.
@@ -50,6 +70,21 @@ type Struct struct {
 	Fields map[string]Field
 	// Tags maps field names to tags
 	Tags map[string]string
+	// Order holds the tags of the fields
+	// in the order in which they are declared.
+	Order []string
+	// Nested maps the names of untagged struct fields to the
+	// reflection information of the structs that they hold.
+	Nested map[string]Nested
+}
+
+// Nested represents an untagged field of a struct type
+// whose own type is a struct, such as Address in Person.Address.
+type Nested struct {
+	Field
+
+	// Struct is the reflection information of the field's type.
+	Struct Struct
 }
 
 // Kind returns the Struct's reflect.Kind.
@@ -61,3 +96,8 @@ func (r Struct) Kind() reflect.Kind {
 func (r Struct) Name() string {
 	return r.value.Type().Name()
 }
+
+// Type returns the Struct's reflect.Type.
+func (r Struct) Type() reflect.Type {
+	return r.value.Type()
+}
@@ -1,7 +1,14 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,8 +31,23 @@ type Manager struct {
 type District struct {
 }
 
+type Café struct {
+	Prénom string `db:"prénom"`
+	Année  int    `db:"année"`
+}
+
 type M map[string]any
 
+type Ids []int
+
+type Team struct {
+	Members []string `db:"members"`
+}
+
+type PersonID int
+
+type Count int
+
 func TestRound(t *testing.T) {
 	var tests = []struct {
 		input             string
@@ -39,14 +61,14 @@ func TestRound(t *testing.T) {
 			"ParsedExpr[stringPart[select] outputPart[tableColumn[p.*] typeField[Person.*]]]",
 			[]any{&Person{}},
 			[]any{&Person{}},
-			"select p.*",
+			"select p.id AS _sqlair_0, p.name AS _sqlair_1, p.address_id AS _sqlair_2",
 		},
 		{
 			"select p.* AS&Person.*",
 			"ParsedExpr[stringPart[select] outputPart[tableColumn[p.*] typeField[Person.*]]]",
 			[]any{&Person{}},
 			[]any{&Person{}},
-			"select p.*",
+			"select p.id AS _sqlair_0, p.name AS _sqlair_1, p.address_id AS _sqlair_2",
 		},
 		{
 			"select p.* as &Person.*, '&notAnOutputExpresion.*' as literal from t",
@@ -57,7 +79,7 @@ func TestRound(t *testing.T) {
 				"stringPart[ as literal from t]]",
 			[]any{&Person{}},
 			[]any{&Person{}},
-			"select p.* ,  '&notAnOutputExpresion.*'  as literal from t",
+			"select p.id AS _sqlair_0, p.name AS _sqlair_1, p.address_id AS _sqlair_2 ,  '&notAnOutputExpresion.*'  as literal from t",
 		},
 		{
 			"select * as &Person.* from t",
@@ -66,7 +88,7 @@ func TestRound(t *testing.T) {
 				"stringPart[ from t]]",
 			[]any{&Person{}},
 			[]any{&Person{}},
-			"select *  from t",
+			"select id AS _sqlair_0, name AS _sqlair_1, address_id AS _sqlair_2  from t",
 		},
 		{
 			"select foo, bar from table where foo = $Person.ID",
@@ -83,7 +105,7 @@ func TestRound(t *testing.T) {
 				"inputPart[Address.ID]]",
 			[]any{&Person{}, &Address{}},
 			[]any{&Person{}, &Address{}},
-			"select address_id, id, name  from table where foo = ?",
+			"select id AS _sqlair_0, name AS _sqlair_1, address_id AS _sqlair_2  from table where foo = ?",
 		},
 		{
 			"select &Person.* from table where foo = $Address.ID",
@@ -93,7 +115,7 @@ func TestRound(t *testing.T) {
 				"inputPart[Address.ID]]",
 			[]any{&Person{}, &Address{}},
 			[]any{&Person{}, &Address{}},
-			"select address_id, id, name  from table where foo = ?",
+			"select id AS _sqlair_0, name AS _sqlair_1, address_id AS _sqlair_2  from table where foo = ?",
 		},
 		{
 			"select foo, bar, &Person.ID from table where foo = 'xx'",
@@ -103,7 +125,7 @@ func TestRound(t *testing.T) {
 				"stringPart[ 'xx']]",
 			[]any{&Person{}},
 			[]any{&Person{}},
-			"select foo, bar, id  from table where foo =  'xx'",
+			"select foo, bar, id AS _sqlair_0  from table where foo =  'xx'",
 		},
 		{
 			"select foo, &Person.ID, bar, baz, &Manager.Name from table where foo = 'xx'",
@@ -115,7 +137,7 @@ func TestRound(t *testing.T) {
 				"stringPart[ 'xx']]",
 			[]any{&Person{}, &Manager{}},
 			[]any{&Person{}, &Manager{}},
-			"select foo, id , bar, baz, manager_name  from table where foo =  'xx'",
+			"select foo, id AS _sqlair_0 , bar, baz, manager_name AS _sqlair_1  from table where foo =  'xx'",
 		},
 		{
 			"SELECT * AS &Person.* FROM person WHERE name = 'Fred'",
@@ -126,7 +148,7 @@ func TestRound(t *testing.T) {
 				"stringPart[ 'Fred']]",
 			[]any{&Person{}},
 			[]any{&Person{}},
-			"SELECT *  FROM person WHERE name =  'Fred'",
+			"SELECT id AS _sqlair_0, name AS _sqlair_1, address_id AS _sqlair_2  FROM person WHERE name =  'Fred'",
 		},
 		{
 			"SELECT &Person.* FROM person WHERE name = 'Fred'",
@@ -136,7 +158,7 @@ func TestRound(t *testing.T) {
 				"stringPart[ 'Fred']]",
 			[]any{&Person{}},
 			[]any{&Person{}},
-			"SELECT address_id, id, name  FROM person WHERE name =  'Fred'",
+			"SELECT id AS _sqlair_0, name AS _sqlair_1, address_id AS _sqlair_2  FROM person WHERE name =  'Fred'",
 		},
 		{
 			"SELECT * AS &Person.*, a.* as &Address.* FROM person, address a WHERE name = 'Fred'",
@@ -148,7 +170,7 @@ func TestRound(t *testing.T) {
 				"stringPart[ 'Fred']]",
 			[]any{&Person{}, &Address{}},
 			[]any{&Person{}, &Address{}},
-			"SELECT * , a.*  FROM person, address a WHERE name =  'Fred'",
+			"SELECT id AS _sqlair_0, name AS _sqlair_1, address_id AS _sqlair_2 , a.id AS _sqlair_3  FROM person, address a WHERE name =  'Fred'",
 		},
 		{
 			"SELECT (a.district, a.street) AS &Address.* FROM address AS a WHERE p.name = 'Fred'",
@@ -179,7 +201,7 @@ func TestRound(t *testing.T) {
 				"stringPart[ 'Fred']]",
 			[]any{&Person{}, &Address{}},
 			[]any{&Person{}, &Address{}},
-			"SELECT p.* , a.district, a.street , (5+7), (col1 * col2) as calculated_value FROM person AS p JOIN address AS a ON p.address_id = a.id WHERE p.name =  'Fred'",
+			"SELECT p.id AS _sqlair_0, p.name AS _sqlair_1, p.address_id AS _sqlair_2 , a.district, a.street , (5+7), (col1 * col2) as calculated_value FROM person AS p JOIN address AS a ON p.address_id = a.id WHERE p.name =  'Fred'",
 		},
 		{
 			"SELECT p.* AS &Person.*, (a.district, a.street) AS &Address.* " +
@@ -193,7 +215,7 @@ func TestRound(t *testing.T) {
 				"stringPart[ 'Fred']]",
 			[]any{&Person{}, &Address{}},
 			[]any{&Person{}, &Address{}},
-			"SELECT p.* , a.district, a.street  FROM person AS p JOIN address AS a ON p .address_id = a.id WHERE p.name =  'Fred'",
+			"SELECT p.id AS _sqlair_0, p.name AS _sqlair_1, p.address_id AS _sqlair_2 , a.district, a.street  FROM person AS p JOIN address AS a ON p .address_id = a.id WHERE p.name =  'Fred'",
 		},
 		{
 			"SELECT p.* AS &Person.*, (a.district, a.street) AS &Address.* " +
@@ -208,7 +230,7 @@ func TestRound(t *testing.T) {
 				"stringPart[)]]",
 			[]any{&Person{}, &Address{}},
 			[]any{&Person{}, &Address{}, &Person{}},
-			"SELECT p.* , a.district, a.street  FROM person AS p JOIN address AS a ON p.address_id = a.id WHERE p.name in (select name from table where table.n = ? )",
+			"SELECT p.id AS _sqlair_0, p.name AS _sqlair_1, p.address_id AS _sqlair_2 , a.district, a.street  FROM person AS p JOIN address AS a ON p.address_id = a.id WHERE p.name in (select name from table where table.n = ? )",
 		},
 		{
 			"SELECT p.* AS &Person.*, (a.district, a.street) AS &Address.* " +
@@ -230,7 +252,7 @@ func TestRound(t *testing.T) {
 				"stringPart[)]]",
 			[]any{&Person{}, &Address{}},
 			[]any{&Person{}, &Address{}, &Person{}, &Person{}, &Address{}, &Person{}},
-			"SELECT p.* , a.district, a.street  FROM person WHERE p.name in (select name from table where table.n = ? ) UNION SELECT p.* , a.district, a.street  FROM person WHERE p.name in (select name from table where table.n = ? )",
+			"SELECT p.id AS _sqlair_0, p.name AS _sqlair_1, p.address_id AS _sqlair_2 , a.district, a.street  FROM person WHERE p.name in (select name from table where table.n = ? ) UNION SELECT p.id AS _sqlair_3, p.name AS _sqlair_4, p.address_id AS _sqlair_5 , a.district, a.street  FROM person WHERE p.name in (select name from table where table.n = ? )",
 		},
 		{
 			"SELECT p.* AS &Person.*, m.* AS &Manager.* " +
@@ -244,7 +266,7 @@ func TestRound(t *testing.T) {
 				"stringPart[ 'Fred']]",
 			[]any{&Person{}, &Manager{}},
 			[]any{&Person{}, &Manager{}},
-			"SELECT p.* , m.*  FROM person AS p JOIN person AS m ON p.manager_id = m.id WHERE p.name =  'Fred'",
+			"SELECT p.id AS _sqlair_0, p.name AS _sqlair_1, p.address_id AS _sqlair_2 , m.manager_name AS _sqlair_3  FROM person AS p JOIN person AS m ON p.manager_id = m.id WHERE p.name =  'Fred'",
 		},
 		//{
 		//	"SELECT (person.*, address.district) AS &M.* " +
@@ -296,7 +318,7 @@ func TestRound(t *testing.T) {
 				"inputPart[Person.address_id]]",
 			[]any{&Person{}, &District{}},
 			[]any{&Person{}, &District{}, &Person{}, &Person{}},
-			"SELECT p.* , a.District  FROM person AS p JOIN address AS a ON p.address_id = a.id WHERE p.name = ?  AND p.address_id = ?",
+			"SELECT p.id AS _sqlair_0, p.name AS _sqlair_1, p.address_id AS _sqlair_2 , a.District  FROM person AS p JOIN address AS a ON p.address_id = a.id WHERE p.name = ?  AND p.address_id = ?",
 		},
 		{
 			"SELECT p.* AS &Person, a.District AS &District " +
@@ -315,17 +337,7 @@ func TestRound(t *testing.T) {
 				"inputPart[Person.address_id]]",
 			[]any{&Address{}, &Person{}, &District{}},
 			[]any{&Person{}, &District{}, &Address{}, &Person{}, &Person{}},
-			"SELECT p.* , a.District  FROM person AS p INNER JOIN address AS a ON p.address_id = ?  WHERE p.name = ?  AND p.address_id = ?",
-		},
-		{
-			"SELECT p.*, a.district " +
-				"FROM person AS p JOIN address AS a ON p.address_id = a.id " +
-				"WHERE p.name = $Person.*",
-			"ParsedExpr[stringPart[SELECT p.*, a.district FROM person AS p JOIN address AS a ON p.address_id = a.id WHERE p.name =] " +
-				"inputPart[Person.*]]",
-			[]any{&Person{}},
-			[]any{&Person{}},
-			"SELECT p.*, a.district FROM person AS p JOIN address AS a ON p.address_id = a.id WHERE p.name = ?",
+			"SELECT p.id AS _sqlair_0, p.name AS _sqlair_1, p.address_id AS _sqlair_2 , a.District  FROM person AS p INNER JOIN address AS a ON p.address_id = ?  WHERE p.name = ?  AND p.address_id = ?",
 		},
 		{
 			"INSERT INTO person (name) VALUES $Person.name",
@@ -336,12 +348,15 @@ func TestRound(t *testing.T) {
 			"INSERT INTO person (name) VALUES ?",
 		},
 		{
-			"INSERT INTO person VALUES $Person.*",
-			"ParsedExpr[stringPart[INSERT INTO person VALUES] " +
-				"inputPart[Person.*]]",
-			[]any{&Person{}},
+			"INSERT INTO person VALUES ($Person.*), ($Person.*)",
+			"ParsedExpr[stringPart[INSERT INTO person VALUES (] " +
+				"inputPart[Person.*] " +
+				"stringPart[), (] " +
+				"inputPart[Person.*] " +
+				"stringPart[)]]",
 			[]any{&Person{}},
-			"INSERT INTO person VALUES ?",
+			[]any{&Person{}, &Person{}},
+			"INSERT INTO person VALUES ( ?, ?, ? ), ( ?, ?, ? )",
 		},
 		{
 			"UPDATE person SET person.address_id = $Address.ID " +
@@ -354,6 +369,316 @@ func TestRound(t *testing.T) {
 			[]any{&Address{}, &Person{}},
 			"UPDATE person SET person.address_id = ?  WHERE person.id = ?",
 		},
+		{
+			"SELECT &Person.* FROM person ORDER BY name ASC",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[ typeField[Person.*]] " +
+				"stringPart[ FROM person ORDER BY name ASC]]",
+			[]any{&Person{}},
+			[]any{&Person{}},
+			"SELECT id AS _sqlair_0, name AS _sqlair_1, address_id AS _sqlair_2  FROM person ORDER BY name ASC",
+		},
+		{
+			"SELECT ascii, asset AS &Person.name, name ASC FROM t",
+			"ParsedExpr[stringPart[SELECT ascii,] " +
+				"outputPart[tableColumn[.asset] typeField[Person.name]] " +
+				"stringPart[, name ASC FROM t]]",
+			[]any{&Person{}},
+			[]any{&Person{}},
+			"SELECT ascii, asset AS _sqlair_0 , name ASC FROM t",
+		},
+		{
+			"SELECT p.id AS &Person.id FROM person AS p ORDER BY (p.name, p.id) ASC",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[p.id] typeField[Person.id]] " +
+				"stringPart[ FROM person AS p ORDER BY (p.name, p.id) ASC]]",
+			[]any{&Person{}},
+			[]any{&Person{}},
+			"SELECT p.id AS _sqlair_0  FROM person AS p ORDER BY (p.name, p.id) ASC",
+		},
+		{
+			"SELECT (a, b) AS pair, ascii AS &Person.id FROM t",
+			"ParsedExpr[stringPart[SELECT (a, b) AS pair,] " +
+				"outputPart[tableColumn[.ascii] typeField[Person.id]] " +
+				"stringPart[ FROM t]]",
+			[]any{&Person{}},
+			[]any{&Person{}},
+			"SELECT (a, b) AS pair, ascii AS _sqlair_0  FROM t",
+		},
+		{
+			"SELECT COUNT(*) AS &Person.id FROM person",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[.COUNT(*)] typeField[Person.id]] " +
+				"stringPart[ FROM person]]",
+			[]any{&Person{}},
+			[]any{&Person{}},
+			"SELECT COUNT(*) AS _sqlair_0  FROM person",
+		},
+		{
+			"SELECT COALESCE(a.name, '') AS &Person.name FROM person AS a",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[.COALESCE(a.name, '')] typeField[Person.name]] " +
+				"stringPart[ FROM person AS a]]",
+			[]any{&Person{}},
+			[]any{&Person{}},
+			"SELECT COALESCE(a.name, '') AS _sqlair_0  FROM person AS a",
+		},
+		{
+			"SELECT (price * qty) AS &Person.id, p.id + 1 AS &Address.id FROM t",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[.(price * qty)] typeField[Person.id]] " +
+				"stringPart[,] " +
+				"outputPart[tableColumn[.p.id + 1] typeField[Address.id]] " +
+				"stringPart[ FROM t]]",
+			[]any{&Person{}, &Address{}},
+			[]any{&Person{}, &Address{}},
+			"SELECT (price * qty) AS _sqlair_0 , p.id + 1 AS _sqlair_1  FROM t",
+		},
+		{
+			"SELECT -id AS &Person.id FROM t",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[.-id] typeField[Person.id]] " +
+				"stringPart[ FROM t]]",
+			[]any{&Person{}},
+			[]any{&Person{}},
+			"SELECT -id AS _sqlair_0  FROM t",
+		},
+		{
+			"SELECT DISTINCT -id AS &Person.id FROM t",
+			"ParsedExpr[stringPart[SELECT DISTINCT] " +
+				"outputPart[tableColumn[.-id] typeField[Person.id]] " +
+				"stringPart[ FROM t]]",
+			[]any{&Person{}},
+			[]any{&Person{}},
+			"SELECT DISTINCT -id AS _sqlair_0  FROM t",
+		},
+		{
+			"SELECT COALESCE(a, b), x FROM t",
+			"ParsedExpr[stringPart[SELECT COALESCE(a, b), x FROM t]]",
+			[]any{},
+			[]any{},
+			"SELECT COALESCE(a, b), x FROM t",
+		},
+		{
+			"SELECT (p.name, a.id) AS (&Person.name, &Address.id) FROM person AS p, address AS a",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[p.name] tableColumn[a.id] typeField[Person.name] typeField[Address.id]] " +
+				"stringPart[ FROM person AS p, address AS a]]",
+			[]any{&Person{}, &Address{}},
+			[]any{&Person{}, &Address{}},
+			"SELECT p.name AS _sqlair_0, a.id AS _sqlair_1  FROM person AS p, address AS a",
+		},
+		{
+			"SELECT (name, id) AS (&Person.name, &Person.id) FROM person",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[.name] tableColumn[.id] typeField[Person.name] typeField[Person.id]] " +
+				"stringPart[ FROM person]]",
+			[]any{&Person{}},
+			[]any{&Person{}},
+			"SELECT name AS _sqlair_0, id AS _sqlair_1  FROM person",
+		},
+		{
+			"SELECT c.prénom AS &Café.prénom FROM café AS c WHERE c.année = $Café.année",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[c.prénom] typeField[Café.prénom]] " +
+				"stringPart[ FROM café AS c WHERE c.année =] " +
+				"inputPart[Café.année]]",
+			[]any{&Café{}},
+			[]any{&Café{}, &Café{}},
+			"SELECT c.prénom AS _sqlair_0  FROM café AS c WHERE c.année = ?",
+		},
+		{
+			"SELECT &Person.* FROM person WHERE id = $1 AND flags&4 <> 0;",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[ typeField[Person.*]] " +
+				"stringPart[ FROM person WHERE id = $1 AND flags&4 <> 0;]]",
+			[]any{&Person{}},
+			[]any{&Person{}},
+			"SELECT id AS _sqlair_0, name AS _sqlair_1, address_id AS _sqlair_2  FROM person WHERE id = $1 AND flags&4 <> 0;",
+		},
+		{
+			"SELECT name\nFROM person\nWHERE id < $Person.id",
+			"ParsedExpr[stringPart[SELECT name\nFROM person\nWHERE id <] " +
+				"inputPart[Person.id]]",
+			[]any{&Person{}},
+			[]any{&Person{}},
+			"SELECT name\nFROM person\nWHERE id < ?",
+		},
+		{
+			"SELECT &Person.* FROM person WHERE id IN ($Ids[:])",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[ typeField[Person.*]] " +
+				"stringPart[ FROM person WHERE id IN (] " +
+				"inputPart[Ids.[:]] " +
+				"stringPart[)]]",
+			[]any{&Person{}, &Ids{}},
+			[]any{&Person{}, &Ids{1, 2, 3}},
+			"SELECT id AS _sqlair_0, name AS _sqlair_1, address_id AS _sqlair_2  FROM person WHERE id IN ( ?, ?, ? )",
+		},
+		{
+			"SELECT name FROM person WHERE name IN ($Team.members[:]) AND id IN ($Ids[:])",
+			"ParsedExpr[stringPart[SELECT name FROM person WHERE name IN (] " +
+				"inputPart[Team.members[:]] " +
+				"stringPart[) AND id IN (] " +
+				"inputPart[Ids.[:]] " +
+				"stringPart[)]]",
+			[]any{&Team{}, &Ids{}},
+			[]any{&Team{Members: []string{"Fred", "Mary"}}, &Ids{}},
+			"SELECT name FROM person WHERE name IN ( ?, ? ) AND id IN ( NULL )",
+		},
+		{
+			"INSERT INTO person (*) VALUES ($Person.*)",
+			"ParsedExpr[stringPart[INSERT INTO person] " +
+				"insertColumnsPart[(*)] " +
+				"stringPart[ VALUES (] " +
+				"inputPart[Person.*] " +
+				"stringPart[)]]",
+			[]any{&Person{}},
+			[]any{&Person{}},
+			"INSERT INTO person (id, name, address_id)  VALUES ( ?, ?, ? )",
+		},
+		{
+			"INSERT INTO person (*) VALUES ($Person.*, $Manager.manager_name, 'x(') " +
+				"ON CONFLICT DO UPDATE SET name = $Address.id",
+			"ParsedExpr[stringPart[INSERT INTO person] " +
+				"insertColumnsPart[(*)] " +
+				"stringPart[ VALUES (] " +
+				"inputPart[Person.*] " +
+				"stringPart[,] " +
+				"inputPart[Manager.manager_name] " +
+				"stringPart[,] " +
+				"stringPart[ 'x('] " +
+				"stringPart[) ON CONFLICT DO UPDATE SET name =] " +
+				"inputPart[Address.id]]",
+			[]any{&Person{}, &Manager{}, &Address{}},
+			[]any{&Person{}, &Manager{}, &Address{}},
+			"INSERT INTO person (id, name, address_id, manager_name)  VALUES ( ?, ?, ? , ? ,  'x(' ) ON CONFLICT DO UPDATE SET name = ?",
+		},
+		{
+			"SELECT COUNT(*) AS &Person.id FROM person WHERE (*) = $Address.id",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[.COUNT(*)] typeField[Person.id]] " +
+				"stringPart[ FROM person WHERE (*) =] " +
+				"inputPart[Address.id]]",
+			[]any{&Person{}, &Address{}},
+			[]any{&Person{}, &Address{}},
+			"SELECT COUNT(*) AS _sqlair_0  FROM person WHERE (*) = ?",
+		},
+		{
+			"INSERT INTO person (*) VALUES $Person[:].*",
+			"ParsedExpr[stringPart[INSERT INTO person] " +
+				"insertColumnsPart[(*)] " +
+				"stringPart[ VALUES] " +
+				"inputPart[Person[:].*]]",
+			[]any{&Person{}},
+			[]any{[]Person{{ID: 1}, {ID: 2}}},
+			"INSERT INTO person (id, name, address_id)  VALUES (?, ?, ?), (?, ?, ?)",
+		},
+		{
+			"UPDATE person SET $Person.{name, address_id} WHERE id = $Person.id",
+			"ParsedExpr[stringPart[UPDATE person SET] " +
+				"inputPart[Person.{name, address_id}] " +
+				"stringPart[ WHERE id =] " +
+				"inputPart[Person.id]]",
+			[]any{&Person{}},
+			[]any{&Person{}, &Person{}},
+			"UPDATE person SET name = ?, address_id = ?  WHERE id = ?",
+		},
+		{
+			"UPDATE person SET $Person.*, updated = 1 WHERE id = $Address.id",
+			"ParsedExpr[stringPart[UPDATE person SET] " +
+				"inputPart[Person.*] " +
+				"stringPart[, updated = 1 WHERE id =] " +
+				"inputPart[Address.id]]",
+			[]any{&Person{}, &Address{}},
+			[]any{&Person{}, &Address{}},
+			"UPDATE person SET id = ?, name = ?, address_id = ? , updated = 1 WHERE id = ?",
+		},
+		{
+			"UPDATE person SET x = 1, $Person.{name} WHERE id = $Person.id",
+			"ParsedExpr[stringPart[UPDATE person SET x = 1,] " +
+				"inputPart[Person.{name}] " +
+				"stringPart[ WHERE id =] " +
+				"inputPart[Person.id]]",
+			[]any{&Person{}},
+			[]any{&Person{}, &Person{}},
+			"UPDATE person SET x = 1, name = ?  WHERE id = ?",
+		},
+		{
+			"UPDATE person SET (name, x) = ($Person.{name}, 1), $Address.*",
+			"ParsedExpr[stringPart[UPDATE person SET (name, x) = (] " +
+				"inputPart[Person.{name}] " +
+				"stringPart[, 1),] " +
+				"inputPart[Address.*]]",
+			[]any{&Person{}, &Address{}},
+			[]any{&Person{}, &Address{}},
+			"UPDATE person SET (name, x) = ( ? , 1), id = ?",
+		},
+		{
+			"SELECT &Person.{name, id} FROM person WHERE (id, name) = ($Person.{ID, name})",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[ typeField[Person.name] typeField[Person.id]] " +
+				"stringPart[ FROM person WHERE (id, name) = (] " +
+				"inputPart[Person.{ID, name}] " +
+				"stringPart[)]]",
+			[]any{&Person{}},
+			[]any{&Person{}, &Person{}},
+			"SELECT name AS _sqlair_0, id AS _sqlair_1  FROM person WHERE (id, name) = ( ?, ? )",
+		},
+		{
+			"SELECT p.* AS &Person.{name, id}, (a.x, a.name) AS &Person.{address_id, Fullname} FROM person AS p, address AS a",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[p.*] typeField[Person.name] typeField[Person.id]] " +
+				"stringPart[,] " +
+				"outputPart[tableColumn[a.x] tableColumn[a.name] typeField[Person.address_id] typeField[Person.Fullname]] " +
+				"stringPart[ FROM person AS p, address AS a]]",
+			[]any{&Person{}},
+			[]any{&Person{}, &Person{}},
+			"SELECT p.name AS _sqlair_0, p.id AS _sqlair_1 , a.x AS _sqlair_2, a.name AS _sqlair_3  FROM person AS p, address AS a",
+		},
+		{
+			"SELECT COUNT(*) AS &Count FROM person WHERE id = $PersonID",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[.COUNT(*)] typeField[Count.]] " +
+				"stringPart[ FROM person WHERE id =] " +
+				"inputPart[PersonID.]]",
+			[]any{Count(0), PersonID(0)},
+			[]any{Count(0), PersonID(7)},
+			"SELECT COUNT(*) AS _sqlair_0  FROM person WHERE id = ?",
+		},
+		{
+			"SELECT p.* AS &Person.*, (m.id, m.name) AS (&boss.id, &Person.name AS boss) " +
+				"FROM person AS p JOIN person AS m ON m.id = $boss.id",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[p.*] typeField[Person.*]] " +
+				"stringPart[,] " +
+				"outputPart[tableColumn[m.id] tableColumn[m.name] typeField[boss.id] typeField[boss.name]] " +
+				"stringPart[ FROM person AS p JOIN person AS m ON m.id =] " +
+				"inputPart[boss.id]]",
+			[]any{&Person{}},
+			[]any{&Person{}, &Person{}, &Person{}},
+			"SELECT p.id AS _sqlair_0, p.name AS _sqlair_1, p.address_id AS _sqlair_2 , m.id AS _sqlair_3, m.name AS _sqlair_4  FROM person AS p JOIN person AS m ON m.id = ?",
+		},
+		{
+			"SELECT name\n    AS &Person.name\nFROM person",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[.name] typeField[Person.name]] " +
+				"stringPart[\nFROM person]]",
+			[]any{&Person{}},
+			[]any{&Person{}},
+			"SELECT name AS _sqlair_0 \nFROM person",
+		},
+		{
+			"SELECT p.*\tAS\t&Person.*,\n\tm.manager_name AS &Manager.manager_name\nFROM person AS p JOIN person AS m",
+			"ParsedExpr[stringPart[SELECT] " +
+				"outputPart[tableColumn[p.*] typeField[Person.*]] " +
+				"stringPart[,] " +
+				"outputPart[tableColumn[m.manager_name] typeField[Manager.manager_name]] " +
+				"stringPart[\nFROM person AS p JOIN person AS m]]",
+			[]any{&Person{}, &Manager{}},
+			[]any{&Person{}, &Manager{}},
+			"SELECT p.id AS _sqlair_0, p.name AS _sqlair_1, p.address_id AS _sqlair_2 , m.manager_name AS _sqlair_3 \nFROM person AS p JOIN person AS m",
+		},
 	}
 
 	parser := NewParser()
@@ -418,7 +743,7 @@ func TestUnresolvedType(t *testing.T) {
 	parsed, err := parser.Parse(sql)
 	assert.Equal(t, nil, err)
 	_, err = parsed.Prepare(&Person{}, &Address{})
-	assert.Equal(t, fmt.Errorf("type info not present (Manager)"), err)
+	assert.Equal(t, fmt.Errorf("%w (Manager)", ErrMissingType), err)
 }
 
 // We can not reflect on nil values
@@ -438,7 +763,7 @@ func TestTypesNotUnique(t *testing.T) {
 	parsed, err := parser.Parse(sql)
 	assert.Equal(t, nil, err)
 	_, err = parsed.Prepare(&Person{}, &Person{})
-	assert.Equal(t, fmt.Errorf("type 'Person' not unique"), err)
+	assert.Equal(t, fmt.Errorf("%w (Person)", ErrDuplicateType), err)
 }
 
 // We return a proper error when we find an unbound string literal
@@ -446,14 +771,14 @@ func TestUnfinishedStringLiteral(t *testing.T) {
 	sql := "select foo from t where x = 'dddd"
 	parser := NewParser()
 	_, err := parser.Parse(sql)
-	assert.Equal(t, fmt.Errorf("missing right quote in string literal"), err)
+	assert.EqualError(t, err, "missing right quote in string literal")
 }
 
 func TestUnfinishedStringLiteralV2(t *testing.T) {
 	sql := "select foo from t where x = \"dddd"
 	parser := NewParser()
 	_, err := parser.Parse(sql)
-	assert.Equal(t, fmt.Errorf("missing right quote in string literal"), err)
+	assert.EqualError(t, err, "missing right quote in string literal")
 }
 
 // We require to end the string literal with the proper quote depending
@@ -462,7 +787,7 @@ func TestUnfinishedStringLiteralV3(t *testing.T) {
 	sql := "select foo from t where x = \"dddd'"
 	parser := NewParser()
 	_, err := parser.Parse(sql)
-	assert.Equal(t, fmt.Errorf("missing right quote in string literal"), err)
+	assert.EqualError(t, err, "missing right quote in string literal")
 }
 
 // Detect bad input DSL pieces
@@ -470,7 +795,7 @@ func TestBadFormatInput(t *testing.T) {
 	sql := "select foo from t where x = $.id"
 	parser := NewParser()
 	_, err := parser.Parse(sql)
-	assert.Equal(t, fmt.Errorf("no qualifier in input expression"), err)
+	assert.EqualError(t, err, "no qualifier in input expression")
 }
 
 // Detect bad input DSL pieces
@@ -478,7 +803,7 @@ func TestBadFormatInputV2(t *testing.T) {
 	sql := "select foo from t where x = $Address."
 	parser := NewParser()
 	_, err := parser.Parse(sql)
-	assert.Equal(t, fmt.Errorf("expecting identifier after 'Address.'"), err)
+	assert.EqualError(t, err, "expecting identifier after 'Address.'")
 }
 
 // Detect bad output DSL pieces
@@ -486,7 +811,7 @@ func TestBadFormatOutput(t *testing.T) {
 	sql := "select foo as && from t"
 	parser := NewParser()
 	_, err := parser.Parse(sql)
-	assert.Equal(t, fmt.Errorf("malformed output expression"), err)
+	assert.EqualError(t, err, "malformed output expression")
 }
 
 // Detect bad output DSL pieces
@@ -494,17 +819,1372 @@ func TestBadFormatOutputV2(t *testing.T) {
 	sql := "select foo as &.bar from t"
 	parser := NewParser()
 	_, err := parser.Parse(sql)
-	assert.Equal(t, fmt.Errorf("malformed output expression"), err)
+	assert.EqualError(t, err, "malformed output expression")
+}
+
+// Detect bad output DSL pieces after a column group
+func TestBadFormatColumnGroupOutput(t *testing.T) {
+	sql := "select (foo, bar) as &.baz from t"
+	parser := NewParser()
+	_, err := parser.Parse(sql)
+	assert.EqualError(t, err, "malformed output expression")
 }
 
 // We return a proper error when the number of parameters do not match
 // the number of DSL pieces in the statement
 func TestNumParemeterMismatch(t *testing.T) {
-	sql := "select foo from t where x = $Address.id and y = $Person.postal_code"
+	sql := "select foo from t where x = $Address.id and y = $Person.address_id"
 	parser := NewParser()
 	parsed, err := parser.Parse(sql)
 	assert.Equal(t, nil, err)
 	prepared, err := parsed.Prepare(&Address{}, &Person{})
+	assert.Equal(t, nil, err)
 	_, err = prepared.Complete(&Address{})
-	assert.Equal(t, fmt.Errorf("parameters mismatch. expected 2, have 1"), err)
+	assert.EqualError(t, err, "parameters mismatch. expected 2, have 1")
+}
+
+// Every column in a column group needs its own output target
+// when a list of targets is given.
+func TestColumnGroupTargetMismatch(t *testing.T) {
+	sql := "select (p.name, a.id, a.district) AS (&Person.name, &Address.id) from t"
+	parser := NewParser()
+	_, err := parser.Parse(sql)
+	assert.EqualError(t, err, "column group has 3 columns but 2 output targets")
+}
+
+// Each type in a column group target list is a separate output
+func TestColumnGroupOutputSpecs(t *testing.T) {
+	sql := "select (p.name, a.id, p.id) AS (&Person.name, &Address.id, &Person.id) from t"
+	parser := NewParser()
+	parsed, err := parser.Parse(sql)
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Person{}, &Address{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []OutputInfo{{[]string{"name", "id"}, "Person"}, {[]string{"id"}, "Address"}}, prepared.OutputSpecs)
+	_, err = prepared.Complete(&Person{})
+	assert.EqualError(t, err, "parameters mismatch. expected 2, have 1")
+}
+
+// A column can go to a field whose tag is not the column name
+func TestRenamedOutputColumn(t *testing.T) {
+	type Citizen struct {
+		Name  string `db:"name"`
+		Total int64  `db:"total"`
+	}
+	sql := "select citizen_name AS &Citizen.name, (citizen_age + citizen_income) AS &Citizen.total " +
+		"from citizens where citizen_name = 'Fred'"
+	parser := NewParser()
+	parsed, err := parser.Parse(sql)
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	completed, err := prepared.Complete(&Citizen{}, &Citizen{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "select citizen_name AS _sqlair_0 , (citizen_age + citizen_income) AS _sqlair_1  "+
+		"from citizens where citizen_name =  'Fred'", completed.Sql())
+
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	var c Citizen
+	err = completed.Scan(parsed.parts, prepared.ArgTypes, &c, &c)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{Name: "Fred", Total: 1030}, c)
+}
+
+// Slices are expanded to one bound value per element
+func TestSliceInput(t *testing.T) {
+	type Names []string
+	type Stats struct {
+		Total int64 `db:"total"`
+	}
+	sql := "select count(*) AS &Stats.total from citizens where citizen_name in ($Names[:])"
+	parser := NewParser()
+	parsed, err := parser.Parse(sql)
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Stats{}, &Names{})
+	assert.Equal(t, nil, err)
+	completed, err := prepared.Complete(&Stats{}, &Names{"Fred", "Mary", "Nobody"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "select count(*) AS _sqlair_0  from citizens where citizen_name in ( ?, ?, ? )", completed.Sql())
+
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	var stats Stats
+	err = completed.Scan(parsed.parts, prepared.ArgTypes, &stats)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2), stats.Total)
+
+	// An empty IN list matches nothing
+	completed, err = prepared.Complete(&Stats{}, &Names{})
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes, &stats)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0), stats.Total)
+
+	// NOT IN can not be given an empty list
+	parsed, err = parser.Parse("select count(*) AS &Stats.total from citizens where citizen_name not in ($Names[:])")
+	assert.Equal(t, nil, err)
+	prepared, err = parsed.Prepare(&Stats{}, &Names{})
+	assert.Equal(t, nil, err)
+	_, err = prepared.Complete(&Stats{}, &Names{})
+	assert.Equal(t, fmt.Errorf("%w: $Names[:] in a NOT IN list", ErrEmptySlice), err)
+	completed, err = prepared.Complete(&Stats{}, &Names{"Fred"})
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes, &stats)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(3), stats.Total)
+}
+
+// Only slices can be expanded
+func TestSliceInputNotASlice(t *testing.T) {
+	sql := "select name from person where id in ($Person.id[:])"
+	parser := NewParser()
+	parsed, err := parser.Parse(sql)
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Person{})
+	assert.Equal(t, nil, err)
+	_, err = prepared.Complete(&Person{})
+	assert.EqualError(t, err, "can not expand Person.id, it is not a slice")
+}
+
+// $Type.* expands to several values, which only fit in
+// the tuples of VALUES and in SET clauses
+func TestStarInputPlacement(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedParsed string
+	}{{
+		"SELECT p.*, a.district " +
+			"FROM person AS p JOIN address AS a ON p.address_id = a.id " +
+			"WHERE p.name = $Person.*",
+		"ParsedExpr[stringPart[SELECT p.*, a.district FROM person AS p JOIN address AS a ON p.address_id = a.id WHERE p.name =] " +
+			"inputPart[Person.*]]",
+	}, {
+		"INSERT INTO person VALUES $Person.*",
+		"ParsedExpr[stringPart[INSERT INTO person VALUES] " +
+			"inputPart[Person.*]]",
+	}, {
+		"INSERT INTO person VALUES (1, 'x', 2) RETURNING ($Person.*)",
+		"ParsedExpr[stringPart[INSERT INTO person VALUES (1,] " +
+			"stringPart[ 'x'] " +
+			"stringPart[, 2) RETURNING (] " +
+			"inputPart[Person.*] " +
+			"stringPart[)]]",
+	}}
+	parser := NewParser()
+	for _, test := range tests {
+		parsed, err := parser.Parse(test.input)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, test.expectedParsed, parsed.String())
+		_, err = parsed.Prepare(&Person{})
+		assert.EqualError(t, err, "input $Person.* can only be used in a VALUES tuple or a SET clause", test.input)
+	}
+
+	// So does a list of fields
+	parsed, err := parser.Parse("SELECT name FROM person WHERE name = $Person.{name, id}")
+	assert.Equal(t, nil, err)
+	_, err = parsed.Prepare(&Person{})
+	assert.EqualError(t, err, "input $Person.{name, id} can only be used in a VALUES tuple or a SET clause")
+}
+
+// $Type.* binds every tagged field and (*) lists their columns
+func TestInsertStruct(t *testing.T) {
+	type Citizen struct {
+		Name   string `db:"citizen_name"`
+		Age    int64  `db:"citizen_age"`
+		Income int64  `db:"citizen_income"`
+	}
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	parser := NewParser()
+
+	parsed, err := parser.Parse("INSERT INTO citizens (*) VALUES ($Citizen.*)")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	completed, err := prepared.Complete(&Citizen{Name: "Jim", Age: 40, Income: 2000})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "INSERT INTO citizens (citizen_name, citizen_age, citizen_income)  VALUES ( ?, ?, ? )", completed.Sql())
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+
+	parsed, err = parser.Parse("SELECT &Citizen.* FROM citizens WHERE citizen_name = 'Jim'")
+	assert.Equal(t, nil, err)
+	prepared, err = parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	completed, err = prepared.Complete(&Citizen{})
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	var c Citizen
+	err = completed.Scan(parsed.parts, prepared.ArgTypes, &c)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{Name: "Jim", Age: 40, Income: 2000}, c)
+}
+
+// A slice of structs is inserted as several rows, split across
+// statements when there are too many values to bind at once.
+func TestBulkInsert(t *testing.T) {
+	type Citizen struct {
+		Name   string `db:"citizen_name"`
+		Age    int64  `db:"citizen_age"`
+		Income int64  `db:"citizen_income"`
+	}
+	type Stats struct {
+		Total int64 `db:"total"`
+	}
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	parser := NewParser()
+
+	parsed, err := parser.Parse("INSERT INTO citizens (*) VALUES $Citizen[:].*")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	citizens := make([]Citizen, 7)
+	for i := range citizens {
+		citizens[i] = Citizen{Name: "Bulk", Age: int64(i), Income: 100}
+	}
+	completed, err := prepared.Complete(citizens)
+	assert.Equal(t, nil, err)
+	// Two rows per statement
+	completed.SetBindLimit(8)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+
+	parsed, err = parser.Parse("SELECT COUNT(*) AS &Stats.total FROM citizens WHERE citizen_name = 'Bulk'")
+	assert.Equal(t, nil, err)
+	prepared, err = parsed.Prepare(&Stats{})
+	assert.Equal(t, nil, err)
+	completed, err = prepared.Complete(&Stats{})
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	var stats Stats
+	err = completed.Scan(parsed.parts, prepared.ArgTypes, &stats)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(7), stats.Total)
+}
+
+// The statements of a bulk insert can run in the transaction of the caller
+func TestBulkInsertTx(t *testing.T) {
+	type Citizen struct {
+		Name   string `db:"citizen_name"`
+		Age    int64  `db:"citizen_age"`
+		Income int64  `db:"citizen_income"`
+	}
+	ctx := context.Background()
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	parser := NewParser()
+	parsed, err := parser.Parse("INSERT INTO citizens (*) VALUES $Citizen[:].*")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	citizens := make([]Citizen, 7)
+	for i := range citizens {
+		citizens[i] = Citizen{Name: "Bulk", Age: int64(i), Income: 100}
+	}
+	parsedCount, err := parser.Parse("SELECT COUNT(*) AS &Count FROM citizens WHERE citizen_name = 'Bulk'")
+	assert.Equal(t, nil, err)
+	countBulk, err := parsedCount.Prepare(Count(0))
+	assert.Equal(t, nil, err)
+
+	for _, commit := range []bool{false, true} {
+		completed, err := prepared.Complete(citizens)
+		assert.Equal(t, nil, err)
+		completed.SetBindLimit(8)
+		tx, err := db.BeginTx(ctx, nil)
+		assert.Equal(t, nil, err)
+		err = completed.ExecTx(ctx, tx, parsed.parts, prepared.ArgTypes)
+		assert.Equal(t, nil, err)
+		if commit {
+			assert.Equal(t, nil, tx.Commit())
+		} else {
+			assert.Equal(t, nil, tx.Rollback())
+		}
+		count, err := Get[Count](ctx, db, countBulk)
+		assert.Equal(t, nil, err)
+		if commit {
+			assert.Equal(t, Count(7), count)
+		} else {
+			assert.Equal(t, Count(0), count)
+		}
+	}
+}
+
+type otherDriver struct{}
+
+func (otherDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("not implemented")
+}
+
+// Unknown drivers get the lowest limit
+func TestBindLimit(t *testing.T) {
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 32766, bindLimit(db.Driver()))
+	assert.Equal(t, defaultBindLimit, bindLimit(otherDriver{}))
+}
+
+// There must be at least one row to insert
+func TestBulkInsertNoRows(t *testing.T) {
+	parser := NewParser()
+	parsed, err := parser.Parse("INSERT INTO person (*) VALUES $Person[:].*")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Person{})
+	assert.Equal(t, nil, err)
+	_, err = prepared.Complete([]Person{})
+	assert.EqualError(t, err, "no rows to insert for Person[:].*")
+}
+
+// A bulk input expands to its own tuples, it can not be in one
+func TestBulkInsertInTuple(t *testing.T) {
+	parser := NewParser()
+	parsed, err := parser.Parse("INSERT INTO person (*) VALUES ($Person[:].*)")
+	assert.Equal(t, nil, err)
+	_, err = parsed.Prepare(&Person{})
+	assert.EqualError(t, err, "input $Person[:].* can only be used right after VALUES, without parentheses")
+}
+
+// Bulk inserts with outputs can not be split in batches
+func TestBulkInsertReturningLimit(t *testing.T) {
+	type Citizen struct {
+		Name string `db:"citizen_name"`
+		Age  int64  `db:"citizen_age"`
+	}
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	parser := NewParser()
+	parsed, err := parser.Parse("INSERT INTO citizens (*) VALUES $Citizen[:].* RETURNING rowid AS &Count")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{}, Count(0))
+	assert.Equal(t, nil, err)
+	citizens := []Citizen{{"Bulk", 1}, {"Bulk", 2}, {"Bulk", 3}}
+
+	var count Count
+	completed, err := prepared.Complete(citizens, &count)
+	assert.Equal(t, nil, err)
+	completed.SetBindLimit(4)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, fmt.Errorf("%w: a bulk insert with outputs binds 6 values, more than 4", ErrBindLimit), err)
+
+	completed, err = prepared.Complete(citizens, &count)
+	assert.Equal(t, nil, err)
+	completed.SetBindLimit(6)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Count(5), count)
+}
+
+// SET $Type.{...} assigns the listed fields
+func TestUpdateSet(t *testing.T) {
+	type Citizen struct {
+		Name   string `db:"citizen_name"`
+		Age    int64  `db:"citizen_age"`
+		Income int64  `db:"citizen_income"`
+	}
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	parser := NewParser()
+
+	parsed, err := parser.Parse("UPDATE citizens SET $Citizen.{citizen_age, Income} " +
+		"WHERE citizen_name = $Citizen.citizen_name")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	fred := Citizen{Name: "Fred", Age: 31, Income: 1200}
+	completed, err := prepared.Complete(&fred, &fred)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "UPDATE citizens SET citizen_age = ?, citizen_income = ?  WHERE citizen_name = ?", completed.Sql())
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+
+	parsed, err = parser.Parse("SELECT &Citizen.* FROM citizens WHERE citizen_name = 'Fred'")
+	assert.Equal(t, nil, err)
+	prepared, err = parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	completed, err = prepared.Complete(&Citizen{})
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	var c Citizen
+	err = completed.Scan(parsed.parts, prepared.ArgTypes, &c)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, fred, c)
+}
+
+// Detect bad field lists
+func TestBadFieldList(t *testing.T) {
+	parser := NewParser()
+	_, err := parser.Parse("UPDATE person SET $Person.{name, } WHERE id = 1")
+	assert.EqualError(t, err, "expecting field name in 'Person.{'")
+	_, err = parser.Parse("UPDATE person SET $Person.{name WHERE id = 1")
+	assert.EqualError(t, err, "missing '}' after fields of 'Person'")
+}
+
+// A single column can not go to a list of fields
+func TestFieldListForColumn(t *testing.T) {
+	parser := NewParser()
+	_, err := parser.Parse("SELECT p.name AS &Person.{id, name} FROM person AS p")
+	assert.EqualError(t, err, "column name can not go to several fields")
+	_, err = parser.Parse("SELECT (a, b, c) AS &Person.{id, name} FROM person AS p")
+	assert.EqualError(t, err, "column group has 3 columns but 2 output targets")
+}
+
+// The fields in the list must be fields of the type
+func TestFieldListUnknownField(t *testing.T) {
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT &Person.{name, typo} FROM person")
+	assert.Equal(t, nil, err)
+	argTypes, err := typesForStatement([]any{&Person{}})
+	assert.Equal(t, nil, err)
+	_, err = generateOutputInfo(parsed.parts[1].(*outputPart), "Person", argTypes)
+	assert.EqualError(t, err, "type Person has no field typo, valid fields are: id, name, address_id")
+}
+
+// Only the fields in the list are filled in
+func TestOutputFieldList(t *testing.T) {
+	type Citizen struct {
+		Name   string `db:"citizen_name"`
+		Age    int64  `db:"citizen_age"`
+		Income int64  `db:"citizen_income"`
+	}
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT &Citizen.{citizen_income, citizen_name} FROM citizens WHERE citizen_name = 'Mark'")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	completed, err := prepared.Complete(&Citizen{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "SELECT citizen_income AS _sqlair_0, citizen_name AS _sqlair_1  FROM citizens WHERE citizen_name =  'Mark'", completed.Sql())
+
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	var c Citizen
+	err = completed.Scan(parsed.parts, prepared.ArgTypes, &c)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{Name: "Mark", Income: 1500}, c)
+}
+
+// Types that are not structs are inputs and outputs on their own
+func TestScalarTypes(t *testing.T) {
+	type Name string
+	type Age int
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT citizen_age AS &Age FROM citizens WHERE citizen_name = $Name")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(Age(0), Name(""))
+	assert.Equal(t, nil, err)
+	var age Age
+	completed, err := prepared.Complete(&age, Name("Mary"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "SELECT citizen_age AS _sqlair_0  FROM citizens WHERE citizen_name = ?", completed.Sql())
+
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes, &age)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Age(25), age)
+}
+
+// Numbers that do not fit in their output are not truncated
+func TestNumberOverflow(t *testing.T) {
+	type Small struct {
+		Name   string `db:"citizen_name"`
+		Income int8   `db:"citizen_income"`
+	}
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT &Small.* FROM citizens WHERE citizen_name = 'Fred'")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Small{})
+	assert.Equal(t, nil, err)
+	var small Small
+	completed, err := prepared.Complete(&small)
+	assert.Equal(t, nil, err)
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes)
+	var typeErr *TypeMismatchError
+	assert.True(t, errors.As(err, &typeErr))
+	assert.Equal(t, "Small.Income", typeErr.Output)
+	assert.Equal(t, int8(0), small.Income)
+
+	var u uint8
+	assert.False(t, assignValue(reflect.ValueOf(&u).Elem(), int64(-1)))
+	assert.False(t, assignValue(reflect.ValueOf(&u).Elem(), int64(256)))
+	assert.True(t, assignValue(reflect.ValueOf(&u).Elem(), int64(255)))
+	assert.Equal(t, uint8(255), u)
+	var i64 int64
+	assert.False(t, assignValue(reflect.ValueOf(&i64).Elem(), uint64(math.MaxUint64)))
+	var f32 float32
+	assert.False(t, assignValue(reflect.ValueOf(&f32).Elem(), math.MaxFloat64))
+	assert.True(t, assignValue(reflect.ValueOf(&f32).Elem(), 1.5))
+}
+
+// Types that are not structs have no fields and need a column
+func TestBadScalarTypes(t *testing.T) {
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT name FROM person WHERE id = $PersonID.id")
+	assert.Equal(t, nil, err)
+	_, err = parsed.Prepare(PersonID(0))
+	assert.EqualError(t, err, "type PersonID is not a struct, it has no field id")
+
+	parsed, err = parser.Parse("SELECT &Count FROM person")
+	assert.Equal(t, nil, err)
+	_, err = parsed.Prepare(Count(0))
+	assert.EqualError(t, err, "output of Count needs a column, as in COUNT(*) AS &Count")
+
+	parsed, err = parser.Parse("SELECT p.* AS &Count FROM person AS p")
+	assert.Equal(t, nil, err)
+	_, err = parsed.Prepare(Count(0))
+	assert.EqualError(t, err, "Count can not hold several columns")
+}
+
+// An input of a struct type needs a field
+func TestStructInputWithoutField(t *testing.T) {
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT name FROM person WHERE name = $Person")
+	assert.Equal(t, nil, err)
+	_, err = parsed.Prepare(&Person{})
+	var inputErr *InputError
+	assert.True(t, errors.As(err, &inputErr))
+	assert.EqualError(t, err, "input $Person needs a field of Person, as in $Person.* or $Person.field")
+}
+
+// Fields of nested structs are reached with a path
+func TestNestedFields(t *testing.T) {
+	type Pay struct {
+		Income int64 `db:"citizen_income"`
+	}
+	type Citizen struct {
+		Name string `db:"citizen_name"`
+		Pay  Pay
+	}
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT &Citizen.name, income AS &Citizen.Pay.citizen_income " +
+		"FROM citizens WHERE citizen_income = $Citizen.Pay.Income.")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "ParsedExpr[stringPart[SELECT] "+
+		"outputPart[ typeField[Citizen.name]] "+
+		"stringPart[,] "+
+		"outputPart[tableColumn[.income] typeField[Citizen.Pay.citizen_income]] "+
+		"stringPart[ FROM citizens WHERE citizen_income =] "+
+		"inputPart[Citizen.Pay.Income] "+
+		"stringPart[.]]", parsed.String())
+
+	parsed, err = parser.Parse("SELECT &Citizen.Name, citizen_income AS &Citizen.Pay.citizen_income " +
+		"FROM citizens WHERE citizen_income = $Citizen.Pay.Income")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []OutputInfo{{[]string{"citizen_name"}, "Citizen"}, {[]string{"Pay.citizen_income"}, "Citizen"}},
+		prepared.OutputSpecs)
+	var c Citizen
+	completed, err := prepared.Complete(&c, &c, &Citizen{Pay: Pay{Income: 1500}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "SELECT citizen_name AS _sqlair_0 , citizen_income AS _sqlair_1  FROM citizens WHERE citizen_income = ?", completed.Sql())
+
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes, &c, &c)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{Name: "Mark", Pay: Pay{Income: 1500}}, c)
+	assert.Equal(t, ScanReport{[]string{"Citizen.citizen_name", "Citizen.Pay.citizen_income"}, nil},
+		completed.ScanReport())
+
+	// &Citizen.* does not fill in the nested struct, strict scans pass
+	parsed, err = parser.Parse("SELECT &Citizen.* FROM citizens WHERE citizen_name = 'Fred'")
+	assert.Equal(t, nil, err)
+	prepared, err = parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	completed, err = prepared.Complete(&c)
+	assert.Equal(t, nil, err)
+	completed.SetScanMode(ScanStrict)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, ScanReport{[]string{"Citizen.citizen_name"}, nil}, completed.ScanReport())
+}
+
+// A type can have an alias for a second output of the same type
+func TestTypeAlias(t *testing.T) {
+	type Citizen struct {
+		Name   string `db:"citizen_name"`
+		Income int64  `db:"citizen_income"`
+	}
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT p.citizen_name AS &Citizen.citizen_name, " +
+		"m.citizen_income AS &Citizen.citizen_income AS richer " +
+		"FROM citizens AS p JOIN citizens AS m ON m.citizen_name = $richer.citizen_name " +
+		"WHERE p.citizen_name = 'Fred'")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []OutputInfo{{[]string{"citizen_name"}, "Citizen"}, {[]string{"citizen_income"}, "richer"}},
+		prepared.OutputSpecs)
+	var fred, richer Citizen
+	completed, err := prepared.Complete(&fred, &richer, &Citizen{Name: "Mary"})
+	assert.Equal(t, nil, err)
+
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes, &fred, &richer)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{Name: "Fred"}, fred)
+	assert.Equal(t, Citizen{Income: 3500}, richer)
+}
+
+// An alias is for a single type and can not hide one
+func TestBadTypeAlias(t *testing.T) {
+	parser := NewParser()
+	_, err := parser.Parse("SELECT &Person.* AS boss, &Address.* AS boss FROM t")
+	assert.EqualError(t, err, "alias boss is already used for Person")
+
+	parsed, err := parser.Parse("SELECT &Person.* AS Address FROM t WHERE id = $Address.id")
+	assert.Equal(t, nil, err)
+	_, err = parsed.Prepare(&Person{}, &Address{})
+	assert.Equal(t, fmt.Errorf("%w: alias Address is also the name of a type", ErrDuplicateType), err)
+}
+
+// Outputs in a RETURNING clause go back into the struct with the inputs
+func TestReturning(t *testing.T) {
+	type Citizen struct {
+		ID   int64  `db:"id"`
+		Name string `db:"citizen_name"`
+		Age  int64  `db:"citizen_age"`
+	}
+	parser := NewParser()
+	parsed, err := parser.Parse("INSERT INTO citizens (citizen_name, citizen_age) " +
+		"VALUES ($Citizen.citizen_name, $Citizen.citizen_age) RETURNING rowid AS &Citizen.id")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	jim := Citizen{Name: "Jim", Age: 40}
+	completed, err := prepared.Complete(&jim, &jim, &jim)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "INSERT INTO citizens (citizen_name, citizen_age) "+
+		"VALUES ( ? , ? ) RETURNING rowid AS _sqlair_0", completed.Sql())
+
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{ID: 5, Name: "Jim", Age: 40}, jim)
+}
+
+// Scan needs a pointer for each output
+func TestScanOutputs(t *testing.T) {
+	type Citizen struct {
+		Name string `db:"citizen_name"`
+	}
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT &Citizen.* FROM citizens")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	completed, err := prepared.Complete(Citizen{})
+	assert.Equal(t, nil, err)
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes)
+	assert.EqualError(t, err, "can not scan into Citizen, it is not a pointer")
+}
+
+// Fields tagged pk of the struct that supplies the row
+// get the id of the inserted row
+func TestInsertId(t *testing.T) {
+	type Citizen struct {
+		ID   int    `db:"rowid,pk"`
+		Name string `db:"citizen_name"`
+	}
+	parser := NewParser()
+	parsed, err := parser.Parse("INSERT INTO citizens (citizen_name) VALUES ($Citizen.{citizen_name})")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	jim := Citizen{Name: "Jim"}
+	completed, err := prepared.Complete(&jim)
+	assert.Equal(t, nil, err)
+
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{ID: 5, Name: "Jim"}, jim)
+
+	// Other statements do not insert rows
+	parsed, err = parser.Parse("UPDATE citizens SET citizen_age = 1 WHERE citizen_name = $Citizen.citizen_name")
+	assert.Equal(t, nil, err)
+	prepared, err = parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	fred := Citizen{Name: "Fred"}
+	completed, err = prepared.Complete(&fred)
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{Name: "Fred"}, fred)
+
+	_, err = db.Exec("CREATE TABLE audit (who TEXT UNIQUE)")
+	assert.Equal(t, nil, err)
+	tests := []struct {
+		sql      string
+		expected Citizen
+	}{
+		// Every input of the row comes from the struct
+		{"INSERT INTO audit (who) VALUES ($Citizen.citizen_name)", Citizen{ID: 1, Name: "Mary"}},
+		{"WITH x AS (SELECT 1) INSERT INTO audit (who) VALUES ($Citizen.{citizen_name})", Citizen{ID: 2, Name: "James"}},
+		// No row is inserted
+		{"INSERT INTO audit (who) VALUES ($Citizen.{citizen_name}) ON CONFLICT DO NOTHING", Citizen{ID: 99, Name: "Mary"}},
+	}
+	for _, test := range tests {
+		parsed, err = parser.Parse(test.sql)
+		assert.Equal(t, nil, err)
+		prepared, err = parsed.Prepare(&Citizen{})
+		assert.Equal(t, nil, err)
+		c := Citizen{ID: 99, Name: test.expected.Name}
+		completed, err = prepared.Complete(&c)
+		assert.Equal(t, nil, err)
+		err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+		assert.Equal(t, nil, err, test.sql)
+		assert.Equal(t, test.expected, c, test.sql)
+	}
+
+	// The structs of a row from several structs are only read from
+	parsed, err = parser.Parse("INSERT INTO audit (who) VALUES ($Citizen.citizen_name || $Citizen.citizen_name)")
+	assert.Equal(t, nil, err)
+	prepared, err = parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	first, second := Citizen{ID: 99, Name: "Pe"}, Citizen{ID: 99, Name: "ter"}
+	completed, err = prepared.Complete(&first, &second)
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 99, first.ID)
+	assert.Equal(t, 99, second.ID)
+
+	// The same struct for every input of the row gets the id
+	type Aged struct {
+		ID   int    `db:"rowid,pk"`
+		Name string `db:"citizen_name"`
+		Age  int    `db:"citizen_age"`
+	}
+	parsed, err = parser.Parse("INSERT INTO citizens (citizen_name, citizen_age) VALUES ($Aged.citizen_name, $Aged.citizen_age)")
+	assert.Equal(t, nil, err)
+	prepared, err = parsed.Prepare(&Aged{})
+	assert.Equal(t, nil, err)
+	ann := Aged{Name: "Ann", Age: 40}
+	completed, err = prepared.Complete(&ann, &ann)
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Aged{ID: 6, Name: "Ann", Age: 40}, ann)
+
+	type Badge struct {
+		ID   string `db:"rowid,pk"`
+		Name string `db:"who"`
+	}
+	parsed, err = parser.Parse("INSERT INTO audit (who) VALUES ($Badge.{who})")
+	assert.Equal(t, nil, err)
+	prepared, err = parsed.Prepare(&Badge{})
+	assert.Equal(t, nil, err)
+	completed, err = prepared.Complete(&Badge{Name: "Mark"})
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	var typeErr *TypeMismatchError
+	assert.True(t, errors.As(err, &typeErr))
+	assert.Equal(t, "Badge.ID", typeErr.Output)
+}
+
+// Every field in the statement must be a field of its type
+func TestUnknownField(t *testing.T) {
+	type Pay struct {
+		Income int64 `db:"income"`
+	}
+	type Citizen struct {
+		Name string `db:"name"`
+		Pay  Pay
+		Age  int64 `db:"age"`
+	}
+	parser := NewParser()
+	for _, sql := range []string{
+		"SELECT name FROM t WHERE id = $Citizen.typo",
+		"SELECT &Citizen.typo FROM t",
+		"SELECT x AS &Citizen.typo FROM t",
+		"SELECT &Citizen.{name, typo} FROM t",
+		"UPDATE t SET $Citizen.{typo}",
+	} {
+		parsed, err := parser.Parse(sql)
+		assert.Equal(t, nil, err)
+		_, err = parsed.Prepare(&Citizen{})
+		assert.EqualError(t, err, "type Citizen has no field typo, valid fields are: name, Pay.income, age", sql)
+	}
+	parsed, err := parser.Parse("SELECT name FROM t WHERE id = $Citizen.Pay.typo")
+	assert.Equal(t, nil, err)
+	_, err = parsed.Prepare(&Citizen{})
+	assert.EqualError(t, err, "type Citizen has no field Pay.typo, valid fields are: name, Pay.income, age")
+}
+
+// t.* AS &Type.* selects the tagged columns only
+func TestExpandTableColumns(t *testing.T) {
+	type Citizen struct {
+		Name string `db:"citizen_name"`
+		Age  int64  `db:"citizen_age"`
+		Note string
+	}
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT c.* AS &Citizen.* FROM citizens AS c WHERE c.citizen_name = 'Mary'")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	var c Citizen
+	completed, err := prepared.Complete(&c)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "SELECT c.citizen_name AS _sqlair_0, c.citizen_age AS _sqlair_1  FROM citizens AS c WHERE c.citizen_name =  'Mary'", completed.Sql())
+
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{Name: "Mary", Age: 25}, c)
+}
+
+// Columns with the same name from different tables go to their own outputs
+func TestJoinColumnAliases(t *testing.T) {
+	type Citizen struct {
+		Name string `db:"citizen_name"`
+		Age  int64  `db:"citizen_age"`
+	}
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT p.* AS &Citizen.*, m.* AS &Citizen.* AS older " +
+		"FROM citizens AS p JOIN citizens AS m ON m.citizen_age > p.citizen_age " +
+		"WHERE p.citizen_name = 'Mark' AND m.citizen_name = 'Fred'")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	var mark, fred Citizen
+	completed, err := prepared.Complete(&mark, &fred)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "SELECT p.citizen_name AS _sqlair_0, p.citizen_age AS _sqlair_1 , "+
+		"m.citizen_name AS _sqlair_2, m.citizen_age AS _sqlair_3  "+
+		"FROM citizens AS p JOIN citizens AS m ON m.citizen_age > p.citizen_age "+
+		"WHERE p.citizen_name =  'Mark'  AND m.citizen_name =  'Fred'", completed.Sql())
+
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{Name: "Mark", Age: 20}, mark)
+	assert.Equal(t, Citizen{Name: "Fred", Age: 30}, fred)
+}
+
+// Columns with the same name in a group get an alias each,
+// the last one is the one that is left in the field
+func TestGroupColumnAliases(t *testing.T) {
+	type Citizen struct {
+		Age int64 `db:"citizen_age"`
+	}
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT (p.citizen_age, m.citizen_age) AS &Citizen.* " +
+		"FROM citizens AS p JOIN citizens AS m " +
+		"WHERE p.citizen_name = 'Mark' AND m.citizen_name = 'Fred'")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	var c Citizen
+	completed, err := prepared.Complete(&c)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "SELECT p.citizen_age AS _sqlair_0, m.citizen_age AS _sqlair_1  "+
+		"FROM citizens AS p JOIN citizens AS m "+
+		"WHERE p.citizen_name =  'Mark'  AND m.citizen_name =  'Fred'", completed.Sql())
+
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{Age: 30}, c)
+}
+
+// PrepareWithSchema checks the columns of the statement against the database
+func TestPrepareWithSchema(t *testing.T) {
+	type Citizen struct {
+		Name string `db:"citizen_name"`
+		Age  int64  `db:"citizen_age"`
+	}
+	type Email struct {
+		Email string `db:"email"`
+	}
+	type BadAge struct {
+		Age string `db:"citizen_age"`
+	}
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	parser := NewParser()
+	var tests = []struct {
+		sql  string
+		arg  any
+		fail string
+	}{
+		{"SELECT c.* AS &Citizen.* FROM citizens AS c WHERE c.citizen_age > 20", &Citizen{}, ""},
+		{"SELECT &Citizen.*, COUNT(*) AS &Count FROM citizens", &Citizen{}, ""},
+		{"WITH c AS (SELECT 1 AS email) SELECT c.email AS &Email.email FROM c", &Email{}, ""},
+		{"INSERT INTO citizens (*) VALUES ($Citizen.*)", &Citizen{}, ""},
+		{"SELECT c.* AS &Email.* FROM citizens AS c", &Email{},
+			"column email not found in table citizens"},
+		{"SELECT &Email.email FROM citizens", &Email{},
+			"column email not found in table citizens"},
+		{"SELECT (c.citizen_name, c.email) AS &Email.* FROM citizens AS c", &Email{},
+			"column email not found in table citizens"},
+		{"INSERT INTO citizens (*) VALUES ($Email.*)", &Email{},
+			"column email not found in table citizens"},
+		{"UPDATE citizens SET $Email.{email} WHERE citizen_name = 'Fred'", &Email{},
+			"column email not found in table citizens"},
+		{"SELECT c.citizen_age AS &BadAge.citizen_age FROM citizens c", &BadAge{},
+			"the column citizens.citizen_age is type int64 but BadAge.Age has type string"},
+		{"SELECT a.* AS &Citizen.* FROM citizens a JOIN citizens AS b ON a.citizen_name = b.citizen_name", &Citizen{}, ""},
+		{"SELECT &Citizen.* FROM main.citizens", &Citizen{}, ""},
+		{"SELECT &Citizen.* FROM citizen", &Citizen{},
+			"table citizen not found in the database"},
+	}
+	for _, test := range tests {
+		parsed, err := parser.Parse(test.sql)
+		assert.Equal(t, nil, err)
+		args := []any{test.arg}
+		if strings.Contains(test.sql, "&Count") {
+			args = append(args, Count(0))
+		}
+		_, err = parsed.PrepareWithSchema(db, args...)
+		if test.fail == "" {
+			assert.Equal(t, nil, err, test.sql)
+		} else {
+			assert.EqualError(t, err, test.fail, test.sql)
+		}
+	}
+}
+
+// The types of information_schema are matched whole, unlike those of SQLite
+func TestDataTypeSort(t *testing.T) {
+	tests := []struct {
+		dataType string
+		colSort  string
+	}{
+		{"integer", "integer"},
+		{"BIGINT", "integer"},
+		{"character varying", "string"},
+		{"double precision", "float"},
+		{"boolean", "bool"},
+		{"interval", ""},
+		{"point", ""},
+		{"timestamp without time zone", ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.colSort, dataTypeSort(test.dataType), test.dataType)
+	}
+	assert.Equal(t, "integer", declaredSort("point"))
+	assert.True(t, compatibleKind(reflect.String, dataTypeSort("interval")))
+	assert.False(t, compatibleKind(reflect.String, dataTypeSort("bigint")))
+}
+
+// Errors can be told apart with errors.Is and errors.As
+func TestTypedErrors(t *testing.T) {
+	type Citizen struct {
+		Name string `db:"citizen_name"`
+		Age  string `db:"citizen_age"`
+	}
+	parser := NewParser()
+	_, err := parser.Parse("SELECT name FROM t WHERE name = 'Fred")
+	var parseErr *ParseError
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, 37, parseErr.Offset)
+	_, err = parser.Parse("UPDATE person SET $Person.{name, } WHERE id = 1")
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, 33, parseErr.Offset)
+
+	parsed, err := parser.Parse("SELECT &Citizen.* FROM citizens WHERE citizen_name = $Citizen.typo")
+	assert.Equal(t, nil, err)
+	_, err = parsed.Prepare(&Citizen{})
+	var fieldErr *UnknownFieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, &UnknownFieldError{"Citizen", "typo", []string{"citizen_name", "citizen_age"}}, fieldErr)
+
+	parsed, err = parser.Parse("SELECT &Citizen.* FROM citizens WHERE citizen_name = $Citizen.citizen_name")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	_, err = prepared.Complete(&Citizen{})
+	var countErr *ArgumentCountError
+	assert.True(t, errors.As(err, &countErr))
+	assert.Equal(t, &ArgumentCountError{"parameters", 2, 1}, countErr)
+
+	fred := Citizen{Name: "Fred"}
+	completed, err := prepared.Complete(&fred, &fred)
+	assert.Equal(t, nil, err)
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes)
+	var typeErr *TypeMismatchError
+	assert.True(t, errors.As(err, &typeErr))
+	assert.Equal(t, "Citizen.Age", typeErr.Output)
+
+	completed, err = prepared.Complete(&fred, &fred)
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes, fred)
+	var kindErr *KindError
+	assert.True(t, errors.As(err, &kindErr))
+	assert.Equal(t, reflect.Pointer, kindErr.Kind)
+
+	parsed, err = parser.Parse("INSERT INTO citizens (*) VALUES $Citizen[:].*")
+	assert.Equal(t, nil, err)
+	prepared, err = parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	_, err = prepared.Complete([]Citizen{})
+	assert.True(t, errors.Is(err, ErrNoInputRows))
+
+	completed, err = prepared.Complete([]Citizen{{Name: "Bulk"}})
+	assert.Equal(t, nil, err)
+	completed.SetBindLimit(1)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.True(t, errors.Is(err, ErrBindLimit))
+
+	parsed, err = parser.Parse("SELECT &Count FROM citizens")
+	assert.Equal(t, nil, err)
+	_, err = parsed.Prepare(Count(0))
+	assert.True(t, errors.As(err, &kindErr))
+	assert.Equal(t, "Count", kindErr.Name)
+
+	parsed, err = parser.Parse("SELECT name FROM t WHERE name = $Citizen.*")
+	assert.Equal(t, nil, err)
+	_, err = parsed.Prepare(&Citizen{})
+	var inputErr *InputError
+	assert.True(t, errors.As(err, &inputErr))
+	assert.Equal(t, "$Citizen.*", inputErr.Input)
+
+	parsed, err = parser.Parse("SELECT &Citizen.* FROM citizen")
+	assert.Equal(t, nil, err)
+	_, err = parsed.PrepareWithSchema(db, &Citizen{})
+	var schemaErr *SchemaError
+	assert.True(t, errors.As(err, &schemaErr))
+	assert.Equal(t, &SchemaError{"citizen", "", "table citizen not found in the database"}, schemaErr)
+
+	// The errors of the driver are kept
+	closed, err := createDb()
+	assert.Equal(t, nil, err)
+	closed.Close()
+	_, err = parsed.PrepareWithSchema(closed, &Citizen{})
+	assert.NotNil(t, errors.Unwrap(err))
+
+	assert.True(t, errors.Is(ErrNoRows, sql.ErrNoRows))
+}
+
+// Scan returns ErrNoRows when the statement has no rows
+func TestScanNoRows(t *testing.T) {
+	type Citizen struct {
+		Name string `db:"citizen_name"`
+	}
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT &Citizen.* FROM citizens WHERE citizen_name = $Citizen.citizen_name")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+
+	nobody := Citizen{Name: "Nobody"}
+	completed, err := prepared.Complete(&nobody, &nobody)
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes)
+	assert.True(t, errors.Is(err, ErrNoRows))
+	assert.Equal(t, Citizen{Name: "Nobody"}, nobody)
+
+	// The rows are closed after the first scan, Scan says so
+	fred := Citizen{Name: "Fred"}
+	completed, err = prepared.Complete(&fred, &fred)
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes)
+	assert.EqualError(t, err, "sql: Rows are closed")
+}
+
+// Output expressions can be split over several lines and use tabs
+func TestMultilineScan(t *testing.T) {
+	type Citizen struct {
+		Name string `db:"citizen_name"`
+		Age  int    `db:"citizen_age"`
+	}
+	parser := NewParser()
+	parsed, err := parser.Parse("SELECT citizen_name\n    AS &Citizen.citizen_name,\n\tcitizen_age\tAS\t&Citizen.citizen_age\n" +
+		"FROM citizens\nWHERE citizen_name = $Citizen.citizen_name")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	c := Citizen{Name: "Mark"}
+	completed, err := prepared.Complete(&c, &c, &c)
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	err = completed.Scan(parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{"Mark", 20}, c)
+}
+
+// Strict scans fail on columns and fields that do not match,
+// lenient ones only report the fields they left untouched
+func TestScanModes(t *testing.T) {
+	type Citizen struct {
+		Name string `db:"citizen_name"`
+		Age  int    `db:"citizen_age"`
+	}
+	parser := NewParser()
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	tests := []struct {
+		sql       string
+		filled    []string
+		untouched []string
+		strictErr string
+	}{{
+		"SELECT &Citizen.* FROM citizens WHERE citizen_name = 'Fred'",
+		[]string{"Citizen.citizen_name", "Citizen.citizen_age"},
+		nil,
+		"",
+	}, {
+		"SELECT &Citizen.{citizen_name} FROM citizens WHERE citizen_name = 'Fred'",
+		[]string{"Citizen.citizen_name"},
+		[]string{"Citizen.citizen_age"},
+		"no column in the results for Citizen.citizen_age",
+	}, {
+		"SELECT &Citizen.*, citizen_income FROM citizens WHERE citizen_name = 'Fred'",
+		[]string{"Citizen.citizen_name", "Citizen.citizen_age"},
+		nil,
+		"column citizen_income of the results goes into no output",
+	}, {
+		"SELECT &Citizen.citizen_name, citizen_age AS &Citizen.citizen_age FROM citizens WHERE citizen_name = 'Fred'",
+		[]string{"Citizen.citizen_name", "Citizen.citizen_age"},
+		nil,
+		"",
+	}}
+	for _, test := range tests {
+		parsed, err := parser.Parse(test.sql)
+		assert.Equal(t, nil, err, test.sql)
+		prepared, err := parsed.Prepare(&Citizen{})
+		assert.Equal(t, nil, err, test.sql)
+		for _, mode := range []ScanMode{ScanLenient, ScanStrict} {
+			var c Citizen
+			outputs := make([]any, len(prepared.OutputSpecs))
+			for i := range outputs {
+				outputs[i] = &c
+			}
+			completed, err := prepared.Complete(outputs...)
+			assert.Equal(t, nil, err, test.sql)
+			completed.SetScanMode(mode)
+			err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+			assert.Equal(t, nil, err, test.sql)
+			err = completed.Scan(parsed.parts, prepared.ArgTypes)
+			if mode == ScanStrict && test.strictErr != "" {
+				// Nothing is written when a strict scan fails
+				assert.EqualError(t, err, test.strictErr, test.sql)
+				assert.Equal(t, Citizen{}, c, test.sql)
+			} else {
+				assert.Equal(t, nil, err, test.sql)
+				assert.Equal(t, "Fred", c.Name, test.sql)
+			}
+			assert.Equal(t, ScanReport{test.filled, test.untouched}, completed.ScanReport(), test.sql)
+		}
+	}
+}
+
+// Get and All return values of the type of the outputs
+func TestGetAndAll(t *testing.T) {
+	type Citizen struct {
+		Name   string `db:"citizen_name"`
+		Age    int    `db:"citizen_age"`
+		Income int    `db:"citizen_income"`
+	}
+	ctx := context.Background()
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+
+	parser := NewParser()
+	prepare := func(sql string, types ...any) *PreparedExpr {
+		parsed, err := parser.Parse(sql)
+		assert.Equal(t, nil, err, sql)
+		prepared, err := parsed.Prepare(types...)
+		assert.Equal(t, nil, err, sql)
+		return prepared
+	}
+
+	fred, err := Get[Citizen](ctx, db, prepare("SELECT &Citizen.* FROM citizens WHERE citizen_name = 'Fred'", Citizen{}))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{"Fred", 30, 1000}, fred)
+
+	// T can be an input too
+	byName := prepare("SELECT &Citizen.* FROM citizens WHERE citizen_name = $Citizen.citizen_name", Citizen{})
+	mark, err := Get[Citizen](ctx, db, byName, Citizen{Name: "Mark"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Citizen{"Mark", 20, 1500}, mark)
+
+	countAll := prepare("SELECT COUNT(*) AS &Count FROM citizens", Count(0))
+	count, err := Get[Count](ctx, db, countAll)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Count(4), count)
+
+	_, err = Get[Citizen](ctx, db, byName, Citizen{Name: "Nobody"})
+	assert.True(t, errors.Is(err, ErrNoRows))
+
+	// A prepared statement runs as many times as needed
+	byIncome := prepare("SELECT &Citizen.* FROM citizens WHERE citizen_income = $Count", Citizen{}, Count(0))
+	rich, err := All[Citizen](ctx, db, byIncome, Count(3500))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []Citizen{{"Mary", 25, 3500}, {"James", 25, 3500}}, rich)
+
+	none, err := All[Citizen](ctx, db, byIncome, Count(0))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(none))
+
+	_, err = Get[Count](ctx, db, byIncome, Count(0))
+	assert.Equal(t, fmt.Errorf("%w: Citizen is not a Count", ErrOutputType), err)
+	{
+		// The same name is not enough, it must be the same type
+		type Count int
+		_, err = Get[Count](ctx, db, countAll)
+		assert.True(t, errors.Is(err, ErrOutputType))
+	}
+	_, err = Get[Citizen](ctx, db, byIncome)
+	assert.EqualError(t, err, "inputs mismatch. expected 1, have 0")
+	_, err = All[Citizen](ctx, db, prepare("DELETE FROM citizens"))
+	assert.Equal(t, fmt.Errorf("%w to go into Citizen", ErrNoOutputs), err)
+}
+
+// Rows can be scanned into maps without any Go type
+func TestScanMaps(t *testing.T) {
+	type Citizen struct {
+		Name string `db:"citizen_name"`
+	}
+	parser := NewParser()
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+
+	parsed, err := parser.Parse("SELECT * FROM citizens WHERE citizen_age = 25")
+	assert.Equal(t, nil, err)
+	prepared, err := parsed.Prepare()
+	assert.Equal(t, nil, err)
+	completed, err := prepared.Complete()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	rows, err := completed.ScanMaps()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []map[string]any{
+		{"citizen_name": "Mary", "citizen_age": int64(25), "citizen_income": int64(3500)},
+		{"citizen_name": "James", "citizen_age": int64(25), "citizen_income": int64(3500)},
+	}, rows)
+
+	// Output columns are named after the output and the column, not after
+	// their alias, so that two outputs can have the same columns
+	parsed, err = parser.Parse("SELECT a.* AS &Citizen.*, b.* AS &Citizen.* AS other, a.citizen_age " +
+		"FROM citizens AS a JOIN citizens AS b ON a.citizen_income = b.citizen_income " +
+		"WHERE a.citizen_name = 'Mary' AND b.citizen_name = 'James'")
+	assert.Equal(t, nil, err)
+	prepared, err = parsed.Prepare(&Citizen{})
+	assert.Equal(t, nil, err)
+	completed, err = prepared.Complete(&Citizen{}, &Citizen{})
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	rows, err = completed.ScanMaps()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []map[string]any{
+		{"Citizen.citizen_name": "Mary", "other.citizen_name": "James", "citizen_age": int64(25)},
+	}, rows)
+
+	// Other columns of the same name would go to the same key
+	parsed, err = parser.Parse("SELECT a.citizen_name, b.citizen_name FROM citizens AS a JOIN citizens AS b")
+	assert.Equal(t, nil, err)
+	prepared, err = parsed.Prepare()
+	assert.Equal(t, nil, err)
+	completed, err = prepared.Complete()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	_, err = completed.ScanMaps()
+	var columnErr *ColumnError
+	assert.True(t, errors.As(err, &columnErr))
+	assert.Equal(t, []string{"citizen_name"}, columnErr.Columns)
+
+	// Statements that are not queries have no rows
+	parsed, err = parser.Parse("UPDATE citizens SET citizen_age = citizen_age WHERE citizen_name = 'Nobody'")
+	assert.Equal(t, nil, err)
+	prepared, err = parsed.Prepare()
+	assert.Equal(t, nil, err)
+	completed, err = prepared.Complete()
+	assert.Equal(t, nil, err)
+	err = completed.Exec(db, parsed.parts, prepared.ArgTypes)
+	assert.Equal(t, nil, err)
+	_, err = completed.ScanMaps()
+	assert.True(t, errors.Is(err, ErrNoResults))
+}
+
+func TestNormalizeValue(t *testing.T) {
+	db, err := createDb()
+	assert.Equal(t, nil, err)
+	_, err = db.Exec("CREATE TABLE files (name TEXT, data BLOB)")
+	assert.Equal(t, nil, err)
+	_, err = db.Exec("INSERT INTO files VALUES (CAST('a.txt' AS BLOB), x'0102')")
+	assert.Equal(t, nil, err)
+	rows, err := db.Query("SELECT name, data, substr(data, 1) FROM files")
+	assert.Equal(t, nil, err)
+	defer rows.Close()
+	columnTypes, err := rows.ColumnTypes()
+	assert.Equal(t, nil, err)
+	assert.True(t, rows.Next())
+	var name, data, expr any
+	assert.Equal(t, nil, rows.Scan(&name, &data, &expr))
+	assert.IsType(t, []byte{}, name)
+	assert.Equal(t, "a.txt", normalizeValue(name, columnTypes[0]))
+	assert.Equal(t, []byte{1, 2}, normalizeValue(data, columnTypes[1]))
+	// The type of an expression is not known
+	assert.Equal(t, "", columnTypes[2].DatabaseTypeName())
+	assert.IsType(t, []byte{}, normalizeValue(expr, columnTypes[2]))
 }